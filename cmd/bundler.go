@@ -15,13 +15,9 @@ func detectBundlerPlan() (plan.Plan, error) {
 		return plan.Plan{}, err
 	}
 
-	pkgMgr := "pnpm"
-	if bundler == plan.BundlerBun {
-		pkgMgr = "bun"
-	}
-
-	if _, err := exec.LookPath(pkgMgr); err != nil {
-		return plan.Plan{}, fmt.Errorf("%s not found: %w", pkgMgr, err)
+	pkgMgr := installer.DetectPackageManager(bundler)
+	if _, err := exec.LookPath(pkgMgr.Bin()); err != nil {
+		return plan.Plan{}, fmt.Errorf("%s not found: %w", pkgMgr.Bin(), err)
 	}
 
 	if bundler == plan.BundlerVite {
@@ -30,19 +26,5 @@ func detectBundlerPlan() (plan.Plan, error) {
 		}
 	}
 
-	return plan.Plan{Bundler: bundler}, nil
-}
-
-func mainEntryFilename(p plan.Plan) string {
-	if p.IsBun() {
-		return "frontend.tsx"
-	}
-	return "main.tsx"
-}
-
-func storybookCommand(p plan.Plan) string {
-	if p.IsBun() {
-		return "bun run storybook dev -p 6006"
-	}
-	return "pnpm storybook dev -p 6006"
+	return plan.Plan{Bundler: bundler, PM: pkgMgr, Stack: installer.RecordedStack()}, nil
 }
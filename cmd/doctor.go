@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/doctor"
+	"github.com/hotslug/go-sparky/internal/installer"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var (
+		bun    bool
+		pmName string
+		asJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check Node, package managers, git, disk space and the npm registry for the selected stack",
+		Long: "Check the toolchain and environment go-sparky needs.\n\n" +
+			"Inside a project the checks follow its bundler and package manager; elsewhere they\n" +
+			"follow --bun and --pm, defaulting to a Vite project with your configured package manager.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := doctorOptions(cmd, bun, pmName)
+			if err != nil {
+				return withExitCode(exitPrecondition, err)
+			}
+
+			checks := doctor.Run(o)
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(checks); err != nil {
+					return err
+				}
+			} else {
+				writeChecks(cmd.OutOrStdout(), o, checks)
+			}
+
+			if doctor.Failed(checks) {
+				// A failed check is a finding, not a usage mistake.
+				cmd.SilenceUsage = true
+				return withExitCode(exitPrecondition, fmt.Errorf("some checks failed; apply the fixes above and rerun go-sparky doctor"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&bun, "bun", false, "Check the requirements of a Bun project")
+	cmd.Flags().StringVar(&pmName, "pm", "", "Package manager to check: "+strings.Join(pm.Names, ", "))
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the checks as JSON")
+	return cmd
+}
+
+// doctorOptions picks the stack to check: flags, then the project in the current directory, then the config file.
+func doctorOptions(cmd *cobra.Command, bun bool, pmName string) (doctor.Options, error) {
+	o := doctor.Options{Bundler: plan.BundlerVite}
+	detected, err := installer.DetectBundler()
+	switch {
+	case cmd.Flags().Changed("bun"):
+		if bun {
+			o.Bundler = plan.BundlerBun
+		}
+	case err == nil:
+		o.Bundler = detected
+	}
+
+	switch {
+	case pmName != "":
+		m, err := pm.Parse(pmName)
+		if err != nil {
+			return o, err
+		}
+		o.PM = m
+	case err == nil && detected == o.Bundler:
+		o.PM = installer.DetectPackageManager(o.Bundler)
+	case o.Bundler == plan.BundlerVite && userConfig.PackageManager != "":
+		m, err := pm.Parse(userConfig.PackageManager)
+		if err != nil {
+			return o, fmt.Errorf("config: %w", err)
+		}
+		o.PM = m
+	default:
+		o.PM = plan.Plan{Bundler: o.Bundler}.PackageManager()
+	}
+	return o, nil
+}
+
+// writeChecks prints the checks with the fix of each warning and failure.
+func writeChecks(w io.Writer, o doctor.Options, checks []doctor.Check) {
+	fmt.Fprintf(w, "Checking a %s project with %s\n\n", o.Bundler, o.PM.Bin())
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s %-14s %s\n", checkSymbol(c.Status), c.Name, c.Detail)
+		if c.Fix == "" {
+			continue
+		}
+		for _, line := range strings.Split(c.Fix, "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+}
+
+func checkSymbol(s doctor.Status) string {
+	switch s {
+	case doctor.OK:
+		return "\033[32m✔\033[0m"
+	case doctor.Warn:
+		return "\033[33m!\033[0m"
+	case doctor.Fail:
+		return "\033[31m✖\033[0m"
+	}
+	return "\033[2m-\033[0m"
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import "errors"
+
+// Exit codes let pipelines tell failure classes apart.
+const (
+	exitFailure      = 1 // anything not classified below
+	exitPrecondition = 2 // invalid flags, config or preset; missing tools; target already exists
+	exitInstall      = 3 // the starter, a setup tool or the dependency install failed
+	exitTemplate     = 4 // writing generated files failed
+)
+
+// exitError attaches an exit code to an error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode classifies err; a nil error stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the process exit code for err.
+func exitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitFailure
+}
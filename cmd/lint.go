@@ -41,7 +41,7 @@ func newLintRelaxCmd() *cobra.Command {
 				return err
 			}
 
-			if err := installer.WriteESLintRelaxed(plan.Plan{Bundler: bundler}); err != nil {
+			if err := transact(func() error { return installer.WriteESLintRelaxed(plan.Plan{Bundler: bundler}) }); err != nil {
 				return err
 			}
 
@@ -64,7 +64,7 @@ func newLintResetCmd() *cobra.Command {
 				return err
 			}
 
-			if err := installer.WriteESLintStrict(plan.Plan{Bundler: bundler}); err != nil {
+			if err := transact(func() error { return installer.WriteESLintStrict(plan.Plan{Bundler: bundler}) }); err != nil {
 				return err
 			}
 
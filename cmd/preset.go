@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/preset"
+	"github.com/hotslug/go-sparky/internal/stack"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newPresetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Save and list named scaffold configurations",
+	}
+
+	cmd.AddCommand(newPresetSaveCmd())
+	cmd.AddCommand(newPresetListCmd())
+	return cmd
+}
+
+func newPresetSaveCmd() *cobra.Command {
+	var (
+		bun    bool
+		repo   bool
+		output string
+		opts   *scaffoldOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save the given scaffold flags as a preset",
+		Example: "  go-sparky preset save dashboard --mantine --no-framer-motion --pm npm\n" +
+			"  go-sparky preset save dashboard --mantine --repo\n" +
+			"  go-sparky vite-setup my-app --preset dashboard",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			bundler := plan.BundlerVite
+			if bun {
+				bundler = plan.BundlerBun
+			}
+
+			if err := opts.applyDefaults(cmd, bundler); err != nil {
+				return err
+			}
+			p, err := opts.plan("", bundler)
+			if err != nil {
+				return err
+			}
+
+			saved := &preset.Preset{Name: name, Plan: p, Options: presetOptions(cmd)}
+
+			path, err := presetPath(name, repo, output)
+			if err != nil {
+				return err
+			}
+			if err := saved.Save(path); err != nil {
+				return err
+			}
+
+			logger.Success("Saved preset " + name + " to " + path)
+			return nil
+		},
+	}
+
+	opts = addScaffoldFlags(cmd)
+	_ = cmd.Flags().MarkHidden("yes")
+	cmd.Flags().BoolVar(&bun, "bun", false, "Save a preset for new-bun instead of vite-setup")
+	cmd.Flags().BoolVar(&repo, "repo", false, "Save into the repository's "+preset.RepoDir+" directory so the team can share it")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the preset to this file instead")
+	return cmd
+}
+
+func newPresetListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List presets in the repository and user preset directories",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			found := false
+			for _, dir := range preset.Dirs() {
+				names, err := preset.List(dir)
+				if err != nil {
+					return err
+				}
+				if len(names) == 0 {
+					continue
+				}
+
+				found = true
+				fmt.Println(dir + ":")
+				for _, name := range names {
+					fmt.Println("  " + name)
+				}
+			}
+
+			if !found {
+				logger.Info("No presets saved yet. Create one with: go-sparky preset save <name> [flags]")
+			}
+			return nil
+		},
+	}
+}
+
+// presetPath returns where preset save writes name.
+func presetPath(name string, repo bool, output string) (string, error) {
+	if output != "" {
+		return output, nil
+	}
+
+	if repo {
+		root := preset.RepoRoot()
+		if root == "" {
+			return "", fmt.Errorf("--repo needs a git repository; run it inside one or use --output")
+		}
+		return filepath.Join(root, preset.RepoDir, name+".json"), nil
+	}
+
+	dir, err := preset.UserDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// presetOptions collects the passed scaffold flags that the plan does not record.
+func presetOptions(cmd *cobra.Command) map[string]string {
+	// A plan with every field set names every flag the plan records.
+	skip := presetFlags(plan.Plan{PM: pm.NPM, Stack: stack.Pinned})
+	for _, name := range []string{"preset", "yes", "force", "bun", "repo", "output"} {
+		skip[name] = ""
+	}
+
+	options := map[string]string{}
+	cmd.LocalFlags().Visit(func(f *pflag.Flag) {
+		if _, ok := skip[f.Name]; !ok {
+			options[f.Name] = f.Value.String()
+		}
+	})
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
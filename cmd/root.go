@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/hotslug/go-sparky/internal/config"
+	"github.com/hotslug/go-sparky/internal/dryrun"
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/registry"
+	"github.com/hotslug/go-sparky/internal/runner"
 	"github.com/spf13/cobra"
 )
 
@@ -14,22 +20,76 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: true,
 }
 
-var flagVerbose bool
+var (
+	flagVerbose bool
+	flagDryRun  string
+	flagOffline bool
+)
+
+// recorder captures side effects when --dry-run is set.
+var recorder *dryrun.Recorder
+
+// userConfig holds the defaults from the system and user config files.
+var userConfig = &config.Config{}
+
+// usesConfig marks commands that take their defaults from the config, so an
+// unreadable config stops them instead of only printing a warning.
+const usesConfig = "usesConfig"
 
 // Execute runs the root command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+
+	if recorder != nil {
+		report := recorder.Report()
+		if flagDryRun == "json" {
+			_ = report.WriteJSON(os.Stdout)
+		} else {
+			_ = report.WriteText(os.Stdout)
+		}
+	}
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "💀 \x1b[35mError:\x1b[0m", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil && cmd.Annotations[usesConfig] != "" {
+			return withExitCode(exitPrecondition, err)
+		}
+		// Commands that take no defaults from the config keep working.
+		configErr := err
+		if configErr != nil {
+			cfg = &config.Config{}
+		}
+		userConfig = cfg
+
+		if !cmd.Flags().Changed("verbose") && userConfig.Verbose != nil {
+			flagVerbose = *userConfig.Verbose
+		}
 		logger.SetVerbose(flagVerbose)
+		registry.SetOffline(flagOffline)
+		if err := startDryRun(); err != nil {
+			return err
+		}
+		if configErr != nil {
+			logger.Warning(configErr.Error() + "; using the built-in defaults")
+		}
+		return nil
 	}
 
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(exitPrecondition, err)
+	})
+
 	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", true, "Enable verbose output (spinners, extra logs)")
+	rootCmd.PersistentFlags().StringVar(&flagDryRun, "dry-run", "", "Print the commands and file changes without running them (text or json)")
+	rootCmd.PersistentFlags().Lookup("dry-run").NoOptDefVal = "text"
+	rootCmd.PersistentFlags().BoolVar(&flagOffline, "offline", false, "Never contact the npm registry for version lookups; use cached or built-in data")
 	rootCmd.AddCommand(newLintCmd())
 	rootCmd.AddCommand(newAddCmd())
 	rootCmd.AddCommand(newRemoveCmd())
@@ -37,5 +97,29 @@ func init() {
 	rootCmd.AddCommand(newViteSetupCmd())
 	rootCmd.AddCommand(newBunSetupCmd())
 	rootCmd.AddCommand(newBunSetupAliasCmd())
+	rootCmd.AddCommand(newPresetCmd())
+	rootCmd.AddCommand(newStatusCmd())
+	rootCmd.AddCommand(newDoctorCmd())
 	rootCmd.AddCommand(newVersionCmd())
 }
+
+// startDryRun swaps the file system and process runner for a recorder.
+// Progress output moves to stderr (or is dropped for JSON) so stdout only carries the report.
+func startDryRun() error {
+	switch flagDryRun {
+	case "":
+		return nil
+	case "text":
+		logger.SetOutput(os.Stderr)
+	case "json":
+		logger.SetOutput(io.Discard)
+	default:
+		return fmt.Errorf("invalid --dry-run format %q (use text or json)", flagDryRun)
+	}
+
+	logger.SetVerbose(false)
+	recorder = dryrun.NewRecorder()
+	fsys.Use(recorder)
+	runner.Use(recorder)
+	return nil
+}
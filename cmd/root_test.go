@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/config"
+	"github.com/hotslug/go-sparky/internal/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExamples_UseKnownCommandsAndFlags(t *testing.T) {
+	var check func(c *cobra.Command)
+	check = func(c *cobra.Command) {
+		for _, line := range strings.Split(c.Example, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			if fields[0] != rootCmd.Name() {
+				t.Errorf("%s example %q does not start with %s", c.CommandPath(), line, rootCmd.Name())
+				continue
+			}
+
+			target, args, err := rootCmd.Find(fields[1:])
+			if err != nil {
+				t.Errorf("%s example %q: %v", c.CommandPath(), line, err)
+				continue
+			}
+			for _, arg := range args {
+				if !strings.HasPrefix(arg, "-") || arg == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+				known := target.Flag(name) != nil
+				if !strings.HasPrefix(arg, "--") {
+					known = target.Flags().ShorthandLookup(name) != nil || target.InheritedFlags().ShorthandLookup(name) != nil
+				}
+				if !known {
+					t.Errorf("%s example %q: %s has no flag %s", c.CommandPath(), line, target.CommandPath(), arg)
+				}
+			}
+		}
+		for _, sub := range c.Commands() {
+			check(sub)
+		}
+	}
+	check(rootCmd)
+}
+
+func TestPreRun_BadConfigOnlyStopsCommandsThatUseIt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"packageManger": "npm"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvVar, path)
+	t.Setenv(config.SystemEnvVar, path)
+	logger.SetOutput(io.Discard)
+	t.Cleanup(func() { logger.SetOutput(os.Stdout) })
+
+	for _, args := range [][]string{{"doctor"}, {"version"}, {"status"}} {
+		c, _, err := rootCmd.Find(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rootCmd.PersistentPreRunE(c, nil); err != nil {
+			t.Errorf("%s: PersistentPreRunE() error = %v, want a warning", c.CommandPath(), err)
+		}
+	}
+	for _, args := range [][]string{{"vite-setup"}, {"new-bun"}, {"preset", "save"}} {
+		c, _, err := rootCmd.Find(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rootCmd.PersistentPreRunE(c, nil); err == nil {
+			t.Errorf("%s: PersistentPreRunE() accepted an invalid config", c.CommandPath())
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/installer"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/preset"
+	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/stack"
+	"github.com/hotslug/go-sparky/internal/version"
+	"github.com/hotslug/go-sparky/internal/wizard"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// scaffoldOptions holds the stack flags shared by vite-setup and new-bun.
+type scaffoldOptions struct {
+	features map[string]*bool
+	styled   bool
+	router   string
+	pm       string
+	stack    string
+	preset   string
+	yes      bool
+
+	noDevServer bool
+	noInstall   bool
+	noGit       bool
+	browsers    bool
+	force       bool
+}
+
+// addScaffoldFlags registers one flag per feature in the installer registry.
+func addScaffoldFlags(cmd *cobra.Command) *scaffoldOptions {
+	opts := &scaffoldOptions{features: map[string]*bool{}}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[usesConfig] = "true"
+
+	for _, f := range installer.Features() {
+		if f.FlagUsage == "" {
+			continue
+		}
+
+		value := new(bool)
+		opts.features[f.Name] = value
+		cmd.Flags().BoolVar(value, f.FlagName(), false, f.FlagUsage)
+	}
+
+	cmd.Flags().BoolVar(&opts.styled, "styled", false, "Use styled App template (requires mantine)")
+	cmd.Flags().StringVar(&opts.router, "router", "", "Add a router with file-based routes: "+strings.Join(installer.RouterKinds, ", "))
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip the interactive wizard and use flags and defaults as given")
+	cmd.Flags().StringVar(&opts.stack, "stack", stack.Pinned, "Package versions: pinned (tested ranges), latest, or a profile file such as another project's .sparky.json")
+	cmd.Flags().StringVar(&opts.pm, "pm", "", "Package manager: "+strings.Join(pm.Names, ", ")+" (default pnpm for Vite, bun for Bun)")
+	cmd.Flags().StringVar(&opts.preset, "preset", "", "Start from a saved preset: a name from the repo or user preset directory, or a path to a preset file")
+	cmd.Flags().BoolVar(&opts.noDevServer, "no-dev-server", false, "Do not start the dev server after scaffolding")
+	cmd.Flags().BoolVar(&opts.noInstall, "no-install", false, "Write package.json without installing dependencies")
+	cmd.Flags().BoolVar(&opts.noGit, "no-git", false, "Skip git init and the initial commit")
+	cmd.Flags().BoolVar(&opts.browsers, "browsers", false, "Download the Playwright browser after installing (requires --playwright)")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Scaffold into a directory that already has files, overwriting any the scaffold generates")
+	return opts
+}
+
+// resolve builds the plan from the wizard when it should run, otherwise from the flags.
+func (o *scaffoldOptions) resolve(cmd *cobra.Command, name string, bundler plan.BundlerType) (plan.Plan, error) {
+	if err := o.applyDefaults(cmd, bundler); err != nil {
+		return plan.Plan{}, err
+	}
+
+	p, err := o.plan(name, bundler)
+	if err != nil || !o.shouldPrompt(cmd) {
+		return p, err
+	}
+
+	return wizard.New(os.Stdin, os.Stderr).Run(p)
+}
+
+// interactive reports whether go-sparky may prompt: stdin is a terminal and neither --yes nor CI is set.
+func (o *scaffoldOptions) interactive() bool {
+	return !o.yes && os.Getenv("CI") == "" && wizard.IsTerminal(os.Stdin)
+}
+
+// shouldPrompt reports whether the wizard runs: go-sparky may prompt and no scaffold flags were passed.
+func (o *scaffoldOptions) shouldPrompt(cmd *cobra.Command) bool {
+	if !o.interactive() {
+		return false
+	}
+
+	changed := false
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		changed = changed || f.Changed
+	})
+	return !changed
+}
+
+// applyDefaults fills every flag the user did not pass, first from the config
+// file and then from --preset, so a preset overrides the config and flags override both.
+func (o *scaffoldOptions) applyDefaults(cmd *cobra.Command, bundler plan.BundlerType) error {
+	values, err := configFlags(bundler)
+	if err != nil {
+		return err
+	}
+
+	source := "config"
+	if o.preset != "" {
+		saved, err := preset.Load(o.preset)
+		if err != nil {
+			return err
+		}
+		if saved.Plan.Bundler != "" && saved.Plan.Bundler != bundler {
+			return fmt.Errorf("preset %s is for %s projects", saved.Name, saved.Plan.Bundler)
+		}
+
+		for name, value := range presetFlags(saved.Plan) {
+			values[name] = value
+		}
+		for name, value := range saved.Options {
+			values[name] = value
+		}
+		source = "preset " + saved.Name
+		logger.Step("Using preset " + saved.Name)
+	}
+
+	for name, value := range values {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("%s sets unknown option --%s", source, name)
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("%s: invalid --%s: %w", source, name, err)
+		}
+	}
+	return nil
+}
+
+// configFlags returns the scaffold flag values set by the config file, skipping
+// features that are not available for bundler.
+func configFlags(bundler plan.BundlerType) (map[string]string, error) {
+	values := map[string]string{}
+	for name, enabled := range userConfig.Features {
+		f, ok := installer.LookupFeature(name)
+		if !ok || f.FlagUsage == "" || f.Selected == nil {
+			return nil, fmt.Errorf("config: %q is not a scaffold feature", name)
+		}
+		// The config applies to every project, so a Vite-only stack must not break new-bun.
+		if f.Bundler != "" && f.Bundler != bundler {
+			continue
+		}
+		values[f.FlagName()] = strconv.FormatBool(enabled != f.Default)
+	}
+
+	// Bun projects always use bun, so the preferred manager only applies to Vite.
+	if userConfig.PackageManager != "" && bundler == plan.BundlerVite {
+		values["pm"] = userConfig.PackageManager
+	}
+	if userConfig.Stack != "" {
+		values["stack"] = userConfig.Stack
+	}
+	if userConfig.DevServer != nil {
+		values["no-dev-server"] = strconv.FormatBool(!*userConfig.DevServer)
+	}
+	return values, nil
+}
+
+// presetFlags returns the scaffold flag values that reproduce p.
+func presetFlags(p plan.Plan) map[string]string {
+	values := map[string]string{}
+	for _, f := range installer.Features() {
+		if f.FlagUsage == "" || f.Selected == nil {
+			continue
+		}
+		values[f.FlagName()] = strconv.FormatBool(f.Enabled(p) != f.Default)
+	}
+
+	values["styled"] = strconv.FormatBool(p.StyledApp)
+	values["router"] = ""
+	switch {
+	case p.ReactRouter:
+		values["router"] = installer.RouterReactRouter
+	case p.TanStackRouter:
+		values["router"] = installer.RouterTanStack
+	}
+	if p.PM != "" {
+		values["pm"] = p.PM.Bin()
+	}
+	if p.Stack != "" {
+		values["stack"] = p.Stack
+	}
+	return values
+}
+
+// plan resolves the flags into a plan for the project in dir, which may be "." or a nested path.
+func (o *scaffoldOptions) plan(dir string, bundler plan.BundlerType) (plan.Plan, error) {
+	p := plan.Plan{Name: dir, Dir: dir, Bundler: bundler}
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return plan.Plan{}, err
+		}
+		p.Name = filepath.Base(abs)
+	}
+
+	for _, f := range installer.Features() {
+		value, ok := o.features[f.Name]
+		if !ok || f.Selected == nil {
+			continue
+		}
+
+		// Default stacks expose --no-<name>; opt-in stacks expose --<name>.
+		*f.Selected(&p) = *value != f.Default
+	}
+
+	if o.styled && !p.Mantine {
+		return plan.Plan{}, fmt.Errorf("--styled requires --mantine")
+	}
+	p.StyledApp = o.styled
+
+	if o.router != "" {
+		f, err := installer.RouterFeature(o.router)
+		if err != nil {
+			return plan.Plan{}, err
+		}
+		*f.Selected(&p) = true
+	}
+
+	if o.pm != "" {
+		m, err := pm.Parse(o.pm)
+		if err != nil {
+			return plan.Plan{}, err
+		}
+		if p.IsBun() && m != pm.Bun {
+			return plan.Plan{}, fmt.Errorf("Bun projects are run by bun; --pm %s is only supported with vite-setup", o.pm)
+		}
+		p.PM = m
+	}
+	p.PM = p.PackageManager()
+
+	if _, err := stack.Load(o.stack); err != nil {
+		return plan.Plan{}, err
+	}
+	p.Stack = o.stack
+
+	p.NoInstall = o.noInstall
+	p.NoGit = o.noGit
+	if o.browsers && !p.Playwright {
+		return plan.Plan{}, fmt.Errorf("--browsers requires --playwright")
+	}
+	p.Browsers = o.browsers
+	p.NoPrompt = !o.interactive()
+
+	if err := installer.ValidatePlan(p); err != nil {
+		return plan.Plan{}, err
+	}
+
+	return p, nil
+}
+
+// runScaffold creates the project directory, installs every stack the plan selects
+// and, unless --no-dev-server is set, starts the dev server.
+func runScaffold(p plan.Plan, o *scaffoldOptions) error {
+	m := p.PackageManager()
+	if _, err := exec.LookPath(m.Bin()); err != nil {
+		return withExitCode(exitPrecondition, fmt.Errorf("%s not found: %w", m.Bin(), err))
+	}
+
+	if p.IsVite() {
+		if err := version.CheckNodeVersion(); err != nil {
+			return withExitCode(exitPrecondition, err)
+		}
+	}
+
+	entries, err := installer.ExistingEntries(p.Dir)
+	if err != nil {
+		return withExitCode(exitPrecondition, err)
+	}
+	if conflicts := installer.Conflicts(entries); len(conflicts) > 0 && !o.force {
+		return withExitCode(exitPrecondition, fmt.Errorf("\x1b[38;2;255;185;0m%s\x1b[0m already contains files the scaffold could overwrite:\n  %s\nMove them away, choose another directory, or pass --force to scaffold over them.", p.Dir, strings.Join(conflicts, "\n  ")))
+	}
+
+	// A monorepo package shares the repository it lives in rather than starting its own.
+	if !p.NoGit && installer.InEnclosingRepo(p.Dir) {
+		logger.Info("Inside an existing git repository; skipping git init and the initial commit.")
+		p.NoGit = true
+	}
+
+	// A failed build deletes a new directory, or restores an existing one, so the next run can start fresh.
+	if err := transact(func() error { return buildProject(p, entries) }); err != nil {
+		return err
+	}
+
+	var next string
+	if p.Dir != "." {
+		next = "→ cd " + p.Dir + "\n"
+	}
+	if p.NoInstall {
+		next += "→ " + m.Bin() + " install\n"
+	}
+	logger.Info("\n⚡ Go Sparky!\n\n" + next + "→ " + m.Run("dev") + "\n\n⚡ Edit src/App.tsx to begin")
+
+	if o.noDevServer || p.NoInstall {
+		return nil
+	}
+
+	logger.Info("\nStarting dev server (press Ctrl+C to stop)...")
+	return runner.Run(m.Bin(), m.RunArgs("dev")...)
+}
+
+// buildProject creates the project directory, or uses the existing one holding
+// entries, and installs every selected stack into it.
+func buildProject(p plan.Plan, entries []string) error {
+	dir, err := filepath.Abs(p.Dir)
+	if err != nil {
+		return err
+	}
+
+	if entries == nil {
+		logger.Step("Creating project directory")
+		if err := fsys.MkdirAll(p.Dir, 0o755); err != nil {
+			return err
+		}
+	} else {
+		logger.Step("Scaffolding into existing directory " + p.Dir)
+	}
+
+	if err := fsys.Chdir(p.Dir); err != nil {
+		return err
+	}
+
+	// create-vite only accepts a directory that is empty apart from .git.
+	empty := !slices.ContainsFunc(entries, func(name string) bool { return name != ".git" })
+	if err := scaffoldBase(p, dir, empty); err != nil {
+		return withExitCode(exitInstall, err)
+	}
+
+	if err := installer.InstallFeatures(p); err != nil {
+		return withExitCode(exitInstall, err)
+	}
+
+	spin := logger.StartSpinner("Finalizing templates")
+	if err := installer.WriteConfigFiles(p); err != nil {
+		spin("Failed to finalize templates")
+		return withExitCode(exitTemplate, err)
+	}
+
+	if err := installer.WriteAppFiles(p); err != nil {
+		spin("Failed to finalize templates")
+		return withExitCode(exitTemplate, err)
+	}
+
+	if err := installer.WriteFeatureFiles(p); err != nil {
+		spin("Failed to finalize templates")
+		return withExitCode(exitTemplate, err)
+	}
+	spin("Templates ready")
+
+	if p.NoInstall {
+		logger.Step("Skipping dependency install (--no-install)")
+	} else if err := installer.InstallDependencies(p); err != nil {
+		return withExitCode(exitInstall, err)
+	} else if err := installer.FinishFeatures(p); err != nil {
+		return withExitCode(exitInstall, err)
+	}
+
+	if err := installer.WriteManifest(p); err != nil {
+		return withExitCode(exitTemplate, err)
+	}
+
+	if p.NoGit {
+		return nil
+	}
+	// Without installed dependencies the lint-staged hook cannot run, so the commit skips hooks.
+	return installer.CreateInitialCommitIfMissing(userConfig.InitialCommitMessage(), !p.NoInstall)
+}
+
+// scaffoldBase runs the bundler's own starter inside the project directory dir.
+// bun init works next to existing files; create-vite runs in a scratch directory unless dir is empty.
+func scaffoldBase(p plan.Plan, dir string, empty bool) error {
+	if p.IsBun() {
+		if err := installer.ScaffoldBunProject(); err != nil {
+			return err
+		}
+		return installer.CleanupBunScaffold()
+	}
+
+	spin := logger.StartSpinner("Scaffolding with Vite (React + TypeScript)")
+	// Set CI to keep create-vite non-interactive.
+	m := p.PackageManager()
+	create := func() error {
+		bin, args := installer.CreateViteArgs(p)
+		return runner.RunQuietEnv(bin, map[string]string{"CI": "1"}, args...)
+	}
+	run := create
+	if !empty {
+		run = func() error { return installer.RunInScratchDir(dir, create) }
+	}
+	if err := run(); err != nil {
+		spin("Failed to scaffold project")
+		return err
+	}
+	spin("Scaffolded Vite project")
+
+	// Berry defaults to Plug'n'Play, which the generated ESLint, Storybook and Docker setups don't support.
+	if m == pm.YarnBerry {
+		return installer.WriteYarnrc()
+	}
+	return nil
+}
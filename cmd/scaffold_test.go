@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/config"
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+func TestConfigFlags_SkipsFeaturesOfOtherBundler(t *testing.T) {
+	restore := userConfig
+	t.Cleanup(func() { userConfig = restore })
+	userConfig = &config.Config{Features: map[string]bool{
+		"vitest":   true,
+		"msw":      true,
+		"bun-test": true,
+		"docker":   true,
+	}}
+
+	tests := []struct {
+		bundler plan.BundlerType
+		want    []string
+		skipped []string
+	}{
+		{plan.BundlerVite, []string{"vitest", "msw", "docker"}, []string{"bun-test"}},
+		{plan.BundlerBun, []string{"bun-test", "docker"}, []string{"vitest", "msw"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.bundler), func(t *testing.T) {
+			values, err := configFlags(tt.bundler)
+			if err != nil {
+				t.Fatalf("configFlags() error = %v", err)
+			}
+			for _, name := range tt.want {
+				if values[name] != "true" {
+					t.Errorf("configFlags()[%q] = %q, want true", name, values[name])
+				}
+			}
+			for _, name := range tt.skipped {
+				if value, ok := values[name]; ok {
+					t.Errorf("configFlags()[%q] = %q, want it skipped for %s", name, value, tt.bundler)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hotslug/go-sparky/internal/installer"
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the detected bundler, stacks, generated file drift and config problems",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := installer.ProjectStatus()
+			if err != nil {
+				return withExitCode(exitPrecondition, err)
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(st)
+			}
+			return writeStatus(cmd.OutOrStdout(), st)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+// writeStatus prints a Status for people.
+func writeStatus(w io.Writer, st *installer.Status) error {
+	fmt.Fprintf(w, "Bundler:         %s (%s)\n", st.Bundler, st.BundlerReason)
+	fmt.Fprintf(w, "Package manager: %s\n", st.PackageManager)
+	switch {
+	case !st.Manifest:
+		fmt.Fprintln(w, "Manifest:        none")
+	case st.Version != "":
+		fmt.Fprintf(w, "Manifest:        written by go-sparky %s\n", st.Version)
+	default:
+		fmt.Fprintln(w, "Manifest:        present")
+	}
+	if st.Stack != "" {
+		fmt.Fprintf(w, "Stack profile:   %s\n", st.Stack)
+	}
+
+	fmt.Fprintln(w, "\nStacks:")
+	if len(st.Stacks) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, s := range st.Stacks {
+		note := ""
+		switch {
+		case s.Detected && !s.Recorded && st.Manifest:
+			note = " (not recorded)"
+		case !s.Detected:
+			note = " (recorded, not found)"
+		}
+		fmt.Fprintf(w, "  %s%s\n", s.Title, note)
+	}
+
+	if len(st.Files) > 0 {
+		fmt.Fprintln(w, "\nGenerated files:")
+		for _, f := range st.Files {
+			fmt.Fprintf(w, "  %-9s %s\n", f.State, f.Path)
+		}
+	}
+
+	fmt.Fprintln(w, "\nIssues:")
+	if len(st.Issues) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, issue := range st.Issues {
+		fmt.Fprintf(w, "  - %s\n", issue)
+	}
+	return nil
+}
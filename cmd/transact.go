@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hotslug/go-sparky/internal/installer"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/txn"
+)
+
+// transact runs fn with its file changes journaled. When fn fails, package.json,
+// the lockfile and every file fn wrote are restored, in the project directory
+// too, and new directories and entries such as node_modules are deleted; fn's
+// error is returned either way.
+func transact(fn func() error) error {
+	// A dry run never touches disk, so there is nothing to roll back.
+	if recorder != nil {
+		return fn()
+	}
+
+	tx, err := txn.Begin(installer.ProjectStateFiles()...)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		logger.Warning("Rolling back changes")
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback incomplete: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
@@ -0,0 +1,147 @@
+// Package config reads the user's default settings for go-sparky.
+//
+// Settings come from two layers: a system-wide file shared by every user
+// (/etc/go-sparky/config.json, %ProgramData%\go-sparky\config.json on
+// Windows, or $GO_SPARKY_SYSTEM_CONFIG) and the user's own file at
+// $GO_SPARKY_CONFIG or, when that is unset, at go-sparky/config.json in the
+// user config directory ($XDG_CONFIG_HOME, usually ~/.config, on Linux).
+// User settings override system ones; command-line flags override both.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// EnvVar overrides the config file location.
+const EnvVar = "GO_SPARKY_CONFIG"
+
+// SystemEnvVar overrides the system-wide config file location.
+const SystemEnvVar = "GO_SPARKY_SYSTEM_CONFIG"
+
+// DefaultCommitMessage is the message of the initial commit in a new project.
+const DefaultCommitMessage = "chore: scaffold project"
+
+// Config holds the user's defaults. Unset fields keep go-sparky's built-in defaults.
+type Config struct {
+	Features       map[string]bool `json:"features,omitempty"`       // feature name (e.g. "docker") → installed by default
+	PackageManager string          `json:"packageManager,omitempty"` // default --pm for Vite projects
+	Stack          string          `json:"stack,omitempty"`          // default --stack
+	Verbose        *bool           `json:"verbose,omitempty"`        // default --verbose
+	DevServer      *bool           `json:"devServer,omitempty"`      // start the dev server after scaffolding (false = --no-dev-server)
+	CommitMessage  string          `json:"commitMessage,omitempty"`  // initial git commit message
+}
+
+// Path returns the config file location.
+func Path() (string, error) {
+	if path := os.Getenv(EnvVar); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-sparky", "config.json"), nil
+}
+
+// SystemPath returns the system-wide config file location.
+func SystemPath() string {
+	if path := os.Getenv(SystemEnvVar); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			return filepath.Join(dir, "go-sparky", "config.json")
+		}
+	}
+	return filepath.Join("/etc", "go-sparky", "config.json")
+}
+
+// Load reads the system and user config files and merges them, the user's
+// settings winning. A missing file yields an empty layer, except when
+// GO_SPARKY_CONFIG or GO_SPARKY_SYSTEM_CONFIG names it explicitly.
+func Load() (*Config, error) {
+	system, err := load(SystemPath(), os.Getenv(SystemEnvVar) != "")
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := Path()
+	if err != nil {
+		return system, nil
+	}
+	user, err := load(path, os.Getenv(EnvVar) != "")
+	if err != nil {
+		return nil, err
+	}
+
+	system.merge(user)
+	return system, nil
+}
+
+// load reads one config file; a missing file is an empty config unless explicit.
+func load(path string, explicit bool) (*Config, error) {
+	data, err := fsys.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return Parse(data, path)
+}
+
+// merge overlays the fields set in o onto c; features merge per name.
+func (c *Config) merge(o *Config) {
+	for name, enabled := range o.Features {
+		if c.Features == nil {
+			c.Features = map[string]bool{}
+		}
+		c.Features[name] = enabled
+	}
+	if o.PackageManager != "" {
+		c.PackageManager = o.PackageManager
+	}
+	if o.Stack != "" {
+		c.Stack = o.Stack
+	}
+	if o.Verbose != nil {
+		c.Verbose = o.Verbose
+	}
+	if o.DevServer != nil {
+		c.DevServer = o.DevServer
+	}
+	if o.CommitMessage != "" {
+		c.CommitMessage = o.CommitMessage
+	}
+}
+
+// Parse decodes config content; path is only used in error messages.
+func Parse(data []byte, path string) (*Config, error) {
+	var c Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Reject unknown keys so a typo does not silently fall back to defaults.
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// InitialCommitMessage returns the message for the initial commit.
+func (c *Config) InitialCommitMessage() string {
+	if c.CommitMessage == "" {
+		return DefaultCommitMessage
+	}
+	return c.CommitMessage
+}
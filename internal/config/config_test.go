@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useSystemConfig points the system layer at a file holding data.
+func useSystemConfig(t *testing.T, data string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "system.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(SystemEnvVar, path)
+}
+
+func TestLoad_MissingDefaultFileIsEmpty(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvVar, "")
+	useSystemConfig(t, "{}")
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.DevServer != nil || c.InitialCommitMessage() != DefaultCommitMessage || len(c.Features) != 0 {
+		t.Fatalf("Load() = %+v, want built-in defaults", c)
+	}
+}
+
+func TestLoad_EnvOverridesLocation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	useSystemConfig(t, "{}")
+	path := filepath.Join(t.TempDir(), "sparky.json")
+	t.Setenv(EnvVar, path)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("Load() succeeded although the file named by GO_SPARKY_CONFIG is missing")
+	}
+
+	data := `{
+  "features": {"framer-motion": false, "docker": true},
+  "packageManager": "npm",
+  "verbose": false,
+  "devServer": false,
+  "commitMessage": "feat: initial"
+}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Features["framer-motion"] || !c.Features["docker"] || c.PackageManager != "npm" {
+		t.Errorf("Load() = %+v, want the file's stack choices", c)
+	}
+	if c.Verbose == nil || *c.Verbose {
+		t.Errorf("Verbose = %v, want false", c.Verbose)
+	}
+	if c.DevServer == nil || *c.DevServer {
+		t.Errorf("DevServer = %v, want false", c.DevServer)
+	}
+	if c.InitialCommitMessage() != "feat: initial" {
+		t.Errorf("InitialCommitMessage() = %q", c.InitialCommitMessage())
+	}
+}
+
+func TestLoad_UserOverridesSystem(t *testing.T) {
+	useSystemConfig(t, `{
+  "features": {"docker": true, "storybook": true},
+  "packageManager": "pnpm",
+  "stack": "pinned",
+  "commitMessage": "chore: company template"
+}`)
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(EnvVar, path)
+	data := `{"features": {"docker": false}, "packageManager": "npm"}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Features["docker"] || !c.Features["storybook"] {
+		t.Errorf("Features = %v, want docker from the user file and storybook from the system file", c.Features)
+	}
+	if c.PackageManager != "npm" || c.Stack != "pinned" || c.InitialCommitMessage() != "chore: company template" {
+		t.Errorf("Load() = %+v, want user values over system ones", c)
+	}
+
+	t.Setenv(SystemEnvVar, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := Load(); err == nil {
+		t.Fatal("Load() succeeded although the file named by GO_SPARKY_SYSTEM_CONFIG is missing")
+	}
+}
+
+func TestParse_RejectsUnknownKeys(t *testing.T) {
+	if _, err := Parse([]byte(`{"packageManger": "npm"}`), "config.json"); err == nil {
+		t.Fatal("Parse() accepted a misspelled key")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package doctor
+
+import "errors"
+
+// freeBytes is not implemented on this platform.
+func freeBytes(string) (uint64, error) {
+	return 0, errors.New("not supported on this platform")
+}
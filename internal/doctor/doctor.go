@@ -0,0 +1,385 @@
+// Package doctor diagnoses the machine go-sparky runs on: toolchain versions,
+// git identity, the working directory and the npm registry. Every check is
+// read-only, and every failed check carries a concrete fix.
+package doctor
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/registry"
+	"github.com/hotslug/go-sparky/internal/version"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	OK   Status = "ok"
+	Warn Status = "warn" // go-sparky works, but a step may fail or be skipped
+	Fail Status = "fail" // the selected stack cannot be scaffolded
+	Skip Status = "skip" // not needed for the selected stack
+)
+
+// Check is the result of one diagnostic.
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"` // set when Status is Warn or Fail
+}
+
+// Options selects the stack the checks are held against.
+type Options struct {
+	Bundler plan.BundlerType
+	PM      pm.Manager
+}
+
+// minDiskSpace is roughly what node_modules of a full stack plus the package manager cache need.
+const minDiskSpace = 1 << 30
+
+// gitMinimum is the first git that supports `git init -b`, which the Husky setup runs.
+const gitMinimum = ">=2.28.0"
+
+// Seams for tests.
+var (
+	output = func(name string, args ...string) (string, error) {
+		out, err := exec.Command(name, args...).Output()
+		return strings.TrimSpace(string(out)), err
+	}
+	lookPath        = exec.LookPath
+	nodeRequirement = version.ViteNodeRequirement
+	freeSpace       = freeBytes
+	client          = &http.Client{Timeout: 5 * time.Second}
+)
+
+// Run performs every check in the current directory.
+func Run(o Options) []Check {
+	checks := []Check{checkNode(o)}
+	for _, m := range []pm.Manager{pm.NPM, pm.PNPM, pm.Yarn, pm.Bun} {
+		checks = append(checks, checkManager(o, m))
+	}
+	return append(checks,
+		checkGit(),
+		checkGitIdentity(),
+		checkCorepack(o),
+		checkWritable("."),
+		checkDiskSpace("."),
+		checkRegistry(),
+	)
+}
+
+// Failed reports whether any check failed.
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkNode(o Options) Check {
+	c := Check{Name: "Node.js"}
+	out, err := output("node", "--version")
+	if o.Bundler == plan.BundlerBun {
+		if err != nil {
+			c.Status, c.Detail = Skip, "not installed (not needed for Bun projects)"
+		} else {
+			c.Status, c.Detail = OK, out
+		}
+		return c
+	}
+
+	requirement := nodeRequirement()
+	if err != nil {
+		c.Status, c.Detail = Fail, "not found; Vite needs "+requirement
+		c.Fix = "Install Node.js LTS:\n  nvm install --lts && nvm use --lts\n  # or: brew install node"
+		return c
+	}
+
+	v, err := version.ParseVersion(out)
+	switch {
+	case err != nil:
+		c.Status, c.Detail = Warn, fmt.Sprintf("could not read the version from %q", out)
+		c.Fix = "Check that `node --version` prints a version such as v22.12.0"
+	case !version.IsVersionSupportedByRequirement(v, requirement):
+		c.Status, c.Detail = Fail, "v"+v.String()+"; Vite needs "+requirement
+		c.Fix = "Upgrade Node.js:\n  nvm install --lts && nvm use --lts\n  # or: brew upgrade node"
+	default:
+		c.Status, c.Detail = OK, "v"+v.String()+" (Vite needs "+requirement+")"
+	}
+	return c
+}
+
+// managerMinimum is the oldest release of each package manager go-sparky is tested with.
+var managerMinimum = map[pm.Manager]string{
+	pm.NPM:  ">=9.0.0",
+	pm.PNPM: ">=9.0.0",
+	pm.Yarn: ">=1.22.0",
+	pm.Bun:  ">=1.2.0", // bun init --react and bunfig static plugins
+}
+
+// checkManager checks m, which is required when it is the selected package manager or Bun runs the project.
+func checkManager(o Options, m pm.Manager) Check {
+	c := Check{Name: m.Bin()}
+	required := o.PM.Bin() == m.Bin() || (m == pm.Bun && o.Bundler == plan.BundlerBun)
+	requirement := managerMinimum[m]
+
+	out, err := output(m.Bin(), "--version")
+	if err != nil {
+		if !required {
+			c.Status, c.Detail = Skip, "not installed"
+			return c
+		}
+		c.Status, c.Detail, c.Fix = Fail, "not found", installFix(m)
+		return c
+	}
+
+	v, err := version.ParseVersion(out)
+	switch {
+	case err != nil:
+		c.Status, c.Detail = Warn, fmt.Sprintf("could not read the version from %q", out)
+		c.Fix = "Check that `" + m.Bin() + " --version` prints a version"
+	case !version.IsVersionSupportedByRequirement(v, requirement):
+		c.Status, c.Detail, c.Fix = Warn, v.String()+"; go-sparky is tested with "+requirement, upgradeFix(m)
+		if required {
+			c.Status = Fail
+		}
+	default:
+		c.Status, c.Detail = OK, v.String()
+		if required {
+			c.Detail += " (selected)"
+		}
+	}
+	return c
+}
+
+func installFix(m pm.Manager) string {
+	switch m {
+	case pm.NPM:
+		return "npm ships with Node.js; reinstall Node.js:\n  nvm install --lts && nvm use --lts"
+	case pm.Bun:
+		return "Install Bun:\n  curl -fsSL https://bun.sh/install | bash\n  # or: brew install oven-sh/bun/bun"
+	}
+	if _, err := lookPath("corepack"); err == nil {
+		return "Enable it through corepack:\n  corepack enable " + m.Bin()
+	}
+	return "Install it globally:\n  npm install -g " + m.Bin()
+}
+
+func upgradeFix(m pm.Manager) string {
+	switch m {
+	case pm.Bun:
+		return "Upgrade Bun:\n  bun upgrade"
+	case pm.NPM:
+		return "Upgrade npm:\n  npm install -g npm@latest"
+	}
+	if viaCorepack(m) {
+		return "Upgrade through corepack:\n  corepack install -g " + m.Bin() + "@latest"
+	}
+	return "Upgrade it globally:\n  npm install -g " + m.Bin() + "@latest"
+}
+
+func checkGit() Check {
+	c := Check{Name: "git"}
+	out, err := output("git", "--version")
+	if err != nil {
+		c.Status, c.Detail = Warn, "not found; new projects will have no repository or pre-commit hook"
+		c.Fix = "Install git (https://git-scm.com/downloads), or scaffold with --no-git"
+		return c
+	}
+
+	v, err := version.ParseVersion(strings.TrimPrefix(out, "git version "))
+	switch {
+	case err != nil:
+		c.Status, c.Detail = Warn, fmt.Sprintf("could not read the version from %q", out)
+		c.Fix = "Check that `git --version` prints a version"
+	case !version.IsVersionSupportedByRequirement(v, gitMinimum):
+		c.Status, c.Detail = Warn, v.String()+"; the Husky setup runs `git init -b`, which needs "+gitMinimum
+		c.Fix = "Upgrade git (https://git-scm.com/downloads), or scaffold with --no-husky"
+	default:
+		c.Status, c.Detail = OK, v.String()
+	}
+	return c
+}
+
+// checkGitIdentity checks the identity the initial commit of a new project is made with.
+func checkGitIdentity() Check {
+	c := Check{Name: "git identity"}
+	if _, err := lookPath("git"); err != nil {
+		c.Status, c.Detail = Skip, "git not installed"
+		return c
+	}
+
+	name, _ := output("git", "config", "user.name")
+	email, _ := output("git", "config", "user.email")
+	var fixes []string
+	if name == "" {
+		fixes = append(fixes, `git config --global user.name "Your Name"`)
+	}
+	if email == "" {
+		fixes = append(fixes, "git config --global user.email you@example.com")
+	}
+	if len(fixes) > 0 {
+		c.Status, c.Detail = Warn, "user.name or user.email is not set; the initial commit will fail"
+		c.Fix = "Set your identity:\n  " + strings.Join(fixes, "\n  ") + "\nor scaffold with --no-git"
+		return c
+	}
+
+	c.Status, c.Detail = OK, name+" <"+email+">"
+	return c
+}
+
+// checkCorepack reports whether corepack provides the selected package manager.
+func checkCorepack(o Options) Check {
+	c := Check{Name: "corepack"}
+	if _, err := lookPath("corepack"); err != nil {
+		c.Status, c.Detail = Skip, "not installed"
+		return c
+	}
+
+	managed := o.PM == pm.PNPM || o.PM == pm.Yarn || o.PM == pm.YarnBerry
+	if !managed {
+		c.Status, c.Detail = Skip, "installed; not used by "+string(o.PM)
+		return c
+	}
+
+	if viaCorepack(o.PM) {
+		c.Status, c.Detail = OK, "enabled for "+o.PM.Bin()
+		return c
+	}
+
+	if _, err := lookPath(o.PM.Bin()); err != nil {
+		c.Status, c.Detail = Warn, "installed but not enabled, so "+o.PM.Bin()+" is not on PATH"
+		c.Fix = "Enable it:\n  corepack enable " + o.PM.Bin()
+		return c
+	}
+
+	if pinned := packageManagerField(); pinned != "" {
+		c.Status, c.Detail = Warn, "package.json pins "+pinned+" but "+o.PM.Bin()+" on PATH is not the corepack shim"
+		c.Fix = "Let corepack run the pinned version:\n  corepack enable " + o.PM.Bin()
+		return c
+	}
+
+	c.Status, c.Detail = OK, "installed; "+o.PM.Bin()+" is installed globally instead"
+	return c
+}
+
+// viaCorepack reports whether the m on PATH is a corepack shim.
+func viaCorepack(m pm.Manager) bool {
+	path, err := lookPath(m.Bin())
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return strings.Contains(filepath.ToSlash(path), "/corepack/")
+}
+
+// packageManagerField returns the packageManager field of package.json in the current directory.
+func packageManagerField() string {
+	f, err := pkgjson.Load("package.json")
+	if err != nil {
+		return ""
+	}
+	var pinned string
+	if ok, err := f.Field("packageManager", &pinned); !ok || err != nil {
+		return ""
+	}
+	return pinned
+}
+
+func checkWritable(dir string) Check {
+	c := Check{Name: "write access"}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+
+	f, err := os.CreateTemp(dir, ".go-sparky-doctor-*")
+	if err != nil {
+		c.Status, c.Detail = Fail, "cannot create files in "+abs
+		c.Fix = "Run go-sparky from a directory you own, or grant write access:\n  chmod u+w " + abs
+		return c
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	c.Status, c.Detail = OK, abs
+	return c
+}
+
+func checkDiskSpace(dir string) Check {
+	c := Check{Name: "disk space"}
+	free, err := freeSpace(dir)
+	if err != nil {
+		c.Status, c.Detail = Skip, "could not read free space: "+err.Error()
+		return c
+	}
+
+	c.Detail = formatBytes(free) + " free"
+	if free < minDiskSpace {
+		c.Status = Warn
+		c.Fix = "Free up at least " + formatBytes(minDiskSpace) + "; node_modules of a full stack and the package manager cache need about that much. Clearing the package manager cache is usually quickest:\n  npm cache clean --force  # or: pnpm store prune / yarn cache clean / bun pm cache rm"
+		return c
+	}
+	c.Status = OK
+	return c
+}
+
+// checkRegistry checks that the registry npm is configured with answers.
+func checkRegistry() Check {
+	c := Check{Name: "npm registry"}
+	url, source := registry.Configured()
+	where := url
+	if source != "" {
+		where += " (custom, from " + source + ")"
+	}
+
+	if registry.Offline() {
+		c.Status, c.Detail = Skip, "not contacted in --offline mode: "+where
+		return c
+	}
+
+	resp, err := client.Get(url + "/-/ping")
+	if err != nil {
+		c.Status, c.Detail = Fail, "cannot reach "+where
+		if source == "" {
+			c.Fix = "Check your network connection or proxy (HTTPS_PROXY), or point npm at a mirror:\n  npm config set registry <url>"
+		} else {
+			c.Fix = "Check the registry set in " + source + ", or remove it to use " + registry.DefaultURL
+		}
+		return c
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		c.Status, c.Detail = Warn, where+" requires authentication"
+		c.Fix = "Log in to the registry:\n  npm login --registry " + url
+	case resp.StatusCode >= 500:
+		c.Status, c.Detail = Fail, fmt.Sprintf("%s returned status %d", where, resp.StatusCode)
+		c.Fix = "Retry later, or check the registry status page"
+	default:
+		c.Status, c.Detail = OK, "reachable: "+where
+	}
+	return c
+}
+
+func formatBytes(n uint64) string {
+	const gib = 1 << 30
+	if n >= gib {
+		return fmt.Sprintf("%.1f GiB", float64(n)/gib)
+	}
+	return fmt.Sprintf("%d MiB", n>>20)
+}
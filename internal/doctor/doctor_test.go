@@ -0,0 +1,128 @@
+package doctor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+)
+
+// fakeTools stubs the version output of installed tools; anything else is missing.
+func fakeTools(t *testing.T, versions map[string]string) {
+	t.Helper()
+	prevOutput, prevLookPath, prevRequirement := output, lookPath, nodeRequirement
+	t.Cleanup(func() { output, lookPath, nodeRequirement = prevOutput, prevLookPath, prevRequirement })
+
+	output = func(name string, args ...string) (string, error) {
+		if v, ok := versions[name]; ok && len(args) == 1 && args[0] == "--version" {
+			return v, nil
+		}
+		return "", exec.ErrNotFound
+	}
+	lookPath = func(name string) (string, error) {
+		if _, ok := versions[name]; ok {
+			return "/usr/local/bin/" + name, nil
+		}
+		return "", exec.ErrNotFound
+	}
+	nodeRequirement = func() string { return ">=20.19.0 || >=22.12.0" }
+}
+
+func TestCheckNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundler plan.BundlerType
+		node    string
+		want    Status
+	}{
+		{"supported", plan.BundlerVite, "v22.12.0", OK},
+		{"too old", plan.BundlerVite, "v18.20.4", Fail},
+		{"missing", plan.BundlerVite, "", Fail},
+		{"missing for bun", plan.BundlerBun, "", Skip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions := map[string]string{}
+			if tt.node != "" {
+				versions["node"] = tt.node
+			}
+			fakeTools(t, versions)
+
+			c := checkNode(Options{Bundler: tt.bundler})
+			if c.Status != tt.want {
+				t.Fatalf("checkNode() = %+v, want status %s", c, tt.want)
+			}
+			if c.Status == Fail && c.Fix == "" {
+				t.Errorf("checkNode() failed without a fix: %+v", c)
+			}
+		})
+	}
+}
+
+func TestCheckManager_OnlySelectedManagerIsRequired(t *testing.T) {
+	fakeTools(t, map[string]string{"npm": "10.8.2", "yarn": "1.15.0", "corepack": "0.29.4"})
+	o := Options{Bundler: plan.BundlerVite, PM: pm.PNPM}
+
+	if c := checkManager(o, pm.PNPM); c.Status != Fail || !strings.Contains(c.Fix, "corepack enable pnpm") {
+		t.Errorf("selected pnpm missing = %+v, want a failure fixed with corepack", c)
+	}
+	if c := checkManager(o, pm.Bun); c.Status != Skip {
+		t.Errorf("unselected bun missing = %+v, want skip", c)
+	}
+	if c := checkManager(o, pm.Yarn); c.Status != Warn {
+		t.Errorf("unselected old yarn = %+v, want warn", c)
+	}
+	if c := checkManager(Options{Bundler: plan.BundlerBun, PM: pm.NPM}, pm.Bun); c.Status != Fail {
+		t.Errorf("bun missing for a Bun project = %+v, want fail", c)
+	}
+}
+
+func TestCheckRegistry_UsesConfiguredRegistry(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("NPM_CONFIG_USERCONFIG", "none")
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/-/ping" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	t.Setenv("npm_config_registry", server.URL+"/")
+
+	if c := checkRegistry(); c.Status != OK || !strings.Contains(c.Detail, "custom, from npm_config_registry") {
+		t.Errorf("checkRegistry() = %+v, want ok from the custom registry", c)
+	}
+
+	status = http.StatusUnauthorized
+	if c := checkRegistry(); c.Status != Warn || !strings.Contains(c.Fix, "npm login --registry "+server.URL) {
+		t.Errorf("checkRegistry() = %+v, want a login warning", c)
+	}
+
+	server.Close()
+	if c := checkRegistry(); c.Status != Fail || !strings.Contains(c.Fix, "npm_config_registry") {
+		t.Errorf("checkRegistry() = %+v, want a failure pointing at the setting", c)
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	prev := freeSpace
+	t.Cleanup(func() { freeSpace = prev })
+
+	freeSpace = func(string) (uint64, error) { return 300 << 20, nil }
+	if c := checkDiskSpace("."); c.Status != Warn || c.Detail != "300 MiB free" {
+		t.Errorf("checkDiskSpace() = %+v, want a warning at 300 MiB", c)
+	}
+
+	freeSpace = func(string) (uint64, error) { return 0, errors.New("statfs failed") }
+	if c := checkDiskSpace("."); c.Status != Skip {
+		t.Errorf("checkDiskSpace() = %+v, want skip when free space is unknown", c)
+	}
+}
@@ -0,0 +1,258 @@
+// Package dryrun records the commands and file writes a go-sparky command would
+// perform without touching disk, and renders them as a tree/diff or JSON report.
+package dryrun
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hotslug/go-sparky/internal/runner"
+)
+
+// OpKind identifies a recorded side effect.
+type OpKind string
+
+const (
+	OpRun    OpKind = "run"
+	OpWrite  OpKind = "write"
+	OpMkdir  OpKind = "mkdir"
+	OpRemove OpKind = "remove"
+)
+
+// Op is one recorded side effect, in execution order.
+type Op struct {
+	Kind    OpKind
+	Path    string // project-relative path for file operations
+	Dir     string // working directory of a command
+	Command runner.Command
+	Data    []byte
+}
+
+// Recorder implements fsys.FS and runner.Executor. Writes land in an in-memory
+// overlay so later reads observe them, while the real disk is only ever read.
+type Recorder struct {
+	cwd     string
+	files   map[string][]byte
+	dirs    map[string]bool
+	removed map[string]bool
+	ops     []Op
+}
+
+// NewRecorder returns an empty recorder rooted at the current directory.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		cwd:     ".",
+		files:   map[string][]byte{},
+		dirs:    map[string]bool{},
+		removed: map[string]bool{},
+	}
+}
+
+// Ops returns the recorded side effects in execution order.
+func (r *Recorder) Ops() []Op {
+	return r.ops
+}
+
+func (r *Recorder) resolve(name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+	return filepath.Clean(filepath.Join(r.cwd, name))
+}
+
+// isRemoved reports whether path or one of its parents was deleted in the overlay.
+func (r *Recorder) isRemoved(path string) bool {
+	for p := path; ; p = filepath.Dir(p) {
+		if r.removed[p] {
+			return true
+		}
+		if p == "." || p == string(filepath.Separator) || p == filepath.Dir(p) {
+			return false
+		}
+	}
+}
+
+// ReadFile returns overlay content first and falls back to disk.
+func (r *Recorder) ReadFile(name string) ([]byte, error) {
+	path := r.resolve(name)
+	if data, ok := r.files[path]; ok {
+		return append([]byte(nil), data...), nil
+	}
+	if r.isRemoved(path) || r.dirs[path] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return os.ReadFile(path)
+}
+
+// ReadDir lists the directory on disk with the overlay's writes and removals applied.
+func (r *Recorder) ReadDir(name string) ([]os.DirEntry, error) {
+	path := r.resolve(name)
+	overlay := r.dirs[path] || r.hasChildren(path)
+
+	entries := map[string]os.DirEntry{}
+	if !r.isRemoved(path) {
+		list, err := os.ReadDir(path)
+		if err != nil && !(overlay && errors.Is(err, fs.ErrNotExist)) {
+			return nil, err
+		}
+		for _, e := range list {
+			if !r.isRemoved(filepath.Join(path, e.Name())) {
+				entries[e.Name()] = e
+			}
+		}
+	} else if !overlay {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	prefix := path + string(filepath.Separator)
+	for p, data := range r.files {
+		rel, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		if child, _, nested := strings.Cut(rel, string(filepath.Separator)); nested {
+			entries[child] = fs.FileInfoToDirEntry(fileInfo{name: child, dir: true})
+		} else {
+			entries[rel] = fs.FileInfoToDirEntry(fileInfo{name: rel, size: int64(len(data))})
+		}
+	}
+	for p := range r.dirs {
+		if filepath.Dir(p) == path {
+			entries[filepath.Base(p)] = fs.FileInfoToDirEntry(fileInfo{name: filepath.Base(p), dir: true})
+		}
+	}
+
+	list := make([]os.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	slices.SortFunc(list, func(a, b os.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return list, nil
+}
+
+// WriteFile records the write and stores the content in the overlay.
+func (r *Recorder) WriteFile(name string, data []byte, _ os.FileMode) error {
+	path := r.resolve(name)
+	r.files[path] = append([]byte(nil), data...)
+	r.ops = append(r.ops, Op{Kind: OpWrite, Path: path, Data: r.files[path]})
+	return nil
+}
+
+// Stat describes overlay entries and falls back to disk.
+func (r *Recorder) Stat(name string) (os.FileInfo, error) {
+	path := r.resolve(name)
+	if data, ok := r.files[path]; ok {
+		return fileInfo{name: filepath.Base(path), size: int64(len(data))}, nil
+	}
+	if r.dirs[path] || r.hasChildren(path) {
+		return fileInfo{name: filepath.Base(path), dir: true}, nil
+	}
+	if r.isRemoved(path) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return os.Stat(path)
+}
+
+func (r *Recorder) hasChildren(dir string) bool {
+	prefix := dir + string(filepath.Separator)
+	for path := range r.files {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mkdir records creating a single directory.
+func (r *Recorder) Mkdir(name string, _ os.FileMode) error {
+	path := r.resolve(name)
+	r.dirs[path] = true
+	delete(r.removed, path)
+	r.ops = append(r.ops, Op{Kind: OpMkdir, Path: path})
+	return nil
+}
+
+// MkdirAll tracks the directory in the overlay; parents of written files are implied.
+func (r *Recorder) MkdirAll(name string, _ os.FileMode) error {
+	path := r.resolve(name)
+	r.dirs[path] = true
+	delete(r.removed, path)
+	return nil
+}
+
+// Remove records deleting a file that exists in the overlay or on disk.
+func (r *Recorder) Remove(name string) error {
+	if _, err := r.Stat(name); err != nil {
+		return err
+	}
+	r.markRemoved(r.resolve(name))
+	return nil
+}
+
+// RemoveAll records deleting a tree; like os.RemoveAll it succeeds when nothing exists.
+func (r *Recorder) RemoveAll(name string) error {
+	r.markRemoved(r.resolve(name))
+	return nil
+}
+
+func (r *Recorder) markRemoved(path string) {
+	prefix := path + string(filepath.Separator)
+	for p := range r.files {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(r.files, p)
+		}
+	}
+	delete(r.dirs, path)
+	r.removed[path] = true
+	r.ops = append(r.ops, Op{Kind: OpRemove, Path: path})
+}
+
+// Chdir moves the virtual working directory without changing the process directory.
+func (r *Recorder) Chdir(dir string) error {
+	r.cwd = r.resolve(dir)
+	return nil
+}
+
+// Execute records the command instead of running it. Probes report a non-zero
+// exit, which models a freshly scaffolded project (no commits, pending changes).
+func (r *Recorder) Execute(c runner.Command) error {
+	if c.Probe {
+		return probeFailed{}
+	}
+
+	r.ops = append(r.ops, Op{Kind: OpRun, Dir: r.cwd, Command: c})
+
+	// `git init` creates .git; mirror it so later git steps are planned too.
+	if c.Name == "git" && len(c.Args) > 0 && c.Args[0] == "init" {
+		r.dirs[r.resolve(".git")] = true
+	}
+	return nil
+}
+
+type probeFailed struct{}
+
+func (probeFailed) Error() string { return "dry run: probe not executed" }
+func (probeFailed) ExitCode() int { return 1 }
+
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi fileInfo) Name() string { return fi.name }
+func (fi fileInfo) Size() int64  { return fi.size }
+func (fi fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o755
+	}
+	return 0o644
+}
+func (fi fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi fileInfo) IsDir() bool        { return fi.dir }
+func (fi fileInfo) Sys() any           { return nil }
@@ -0,0 +1,132 @@
+package dryrun
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/runner"
+)
+
+func TestRecorder_OverlayAndReport(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("existing.txt", []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("same.txt", []byte("same\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("gone.txt", []byte("bye\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRecorder()
+	if err := r.Mkdir("app", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteFile("app/new.txt", []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := r.ReadFile("app/new.txt"); err != nil || string(data) != "hello\n" {
+		t.Fatalf("ReadFile() = %q, %v; want overlay content", data, err)
+	}
+	if err := r.WriteFile("existing.txt", []byte("a\nB\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteFile("same.txt", []byte("same\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Remove("gone.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Stat("gone.txt"); !os.IsNotExist(err) {
+		t.Fatalf("Stat(removed) error = %v, want not exist", err)
+	}
+	if err := r.Execute(runner.Command{Name: "pnpm", Args: []string{"install"}}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := runner.Succeeds("true"); !ok || err != nil {
+		t.Fatalf("real probe should be unaffected, got %v, %v", ok, err)
+	}
+
+	if _, err := os.Stat("app"); !os.IsNotExist(err) {
+		t.Fatalf("dry run touched disk: %v", err)
+	}
+
+	report := r.Report()
+	if len(report.Commands) != 1 || strings.Join(report.Commands[0].Argv, " ") != "pnpm install" {
+		t.Fatalf("Commands = %+v", report.Commands)
+	}
+
+	actions := map[string]string{}
+	for _, f := range report.Files {
+		actions[f.Path] = f.Action
+	}
+	want := map[string]string{
+		"app/new.txt":  ActionCreate,
+		"existing.txt": ActionOverwrite,
+		"same.txt":     ActionUnchanged,
+		"gone.txt":     ActionDelete,
+	}
+	for path, action := range want {
+		if actions[path] != action {
+			t.Errorf("%s action = %q, want %q", path, actions[path], action)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	got := UnifiedDiff("f.txt", "a\nb\nc\n", "a\nB\nc\nd\n")
+	want := "--- f.txt\n+++ f.txt\n@@ -1,3 +1,4 @@\n a\n-b\n+B\n c\n+d\n"
+	if got != want {
+		t.Fatalf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	if UnifiedDiff("f.txt", "same", "same") != "" {
+		t.Fatal("UnifiedDiff() of equal inputs should be empty")
+	}
+}
+
+func TestRecorder_ReadDirAppliesOverlay(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(".husky/_", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".husky/pre-commit", []byte("lint-staged\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRecorder()
+	if err := r.Remove(".husky/pre-commit"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteFile(".husky/commit-msg/hook", []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteFile(".husky/pre-push", []byte("test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := r.ReadDir(".husky")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		got = append(got, name)
+	}
+	if want := "_/ commit-msg/ pre-push"; strings.Join(got, " ") != want {
+		t.Fatalf("ReadDir() = %v, want %s", got, want)
+	}
+
+	if err := r.RemoveAll(".husky"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadDir(".husky"); !os.IsNotExist(err) {
+		t.Fatalf("ReadDir(removed) error = %v, want not exist", err)
+	}
+}
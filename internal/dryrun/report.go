@@ -0,0 +1,221 @@
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File actions in a report.
+const (
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+	ActionUnchanged = "unchanged"
+	ActionDelete    = "delete"
+)
+
+// Report is the net effect of a dry run.
+type Report struct {
+	Commands []CommandReport `json:"commands"`
+	Files    []FileReport    `json:"files"`
+}
+
+// CommandReport is one command that would run, in order.
+type CommandReport struct {
+	Dir         string            `json:"dir"`
+	Argv        []string          `json:"argv"`
+	Env         map[string]string `json:"env,omitempty"`
+	Interactive bool              `json:"interactive,omitempty"`
+}
+
+// FileReport is the final state of one file compared with the disk.
+type FileReport struct {
+	Path    string `json:"path"`
+	Action  string `json:"action"`
+	Content string `json:"content,omitempty"` // new content of created text files
+	Diff    string `json:"diff,omitempty"`    // unified diff of overwritten text files
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// Report summarizes the recorded operations against the current disk state.
+func (r *Recorder) Report() Report {
+	report := Report{Commands: []CommandReport{}, Files: []FileReport{}}
+
+	touched := map[string]bool{}
+	for _, op := range r.ops {
+		switch op.Kind {
+		case OpRun:
+			report.Commands = append(report.Commands, CommandReport{
+				Dir:         filepath.ToSlash(op.Dir),
+				Argv:        append([]string{op.Command.Name}, op.Command.Args...),
+				Env:         op.Command.Env,
+				Interactive: op.Command.Stdin,
+			})
+		case OpWrite, OpRemove:
+			touched[op.Path] = true
+		}
+	}
+
+	paths := make([]string, 0, len(touched))
+	for path := range touched {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		onDisk, diskErr := os.ReadFile(path)
+		_, statErr := os.Stat(path)
+		exists := statErr == nil
+		data, written := r.files[path]
+
+		file := FileReport{Path: filepath.ToSlash(path)}
+		switch {
+		case written && !exists:
+			file.Action = ActionCreate
+			if isBinary(data) {
+				file.Binary = true
+			} else {
+				file.Content = string(data)
+			}
+		case written && diskErr == nil && bytes.Equal(onDisk, data):
+			file.Action = ActionUnchanged
+		case written:
+			file.Action = ActionOverwrite
+			if isBinary(data) || isBinary(onDisk) {
+				file.Binary = true
+			} else {
+				file.Diff = UnifiedDiff(file.Path, string(onDisk), string(data))
+			}
+		case exists && r.isRemoved(path):
+			file.Action = ActionDelete
+		default:
+			continue
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	return report
+}
+
+// WriteJSON renders the report for tooling such as code review bots.
+func (rep Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// WriteText renders the report as a command list, a file tree and diffs of changed files.
+func (rep Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("Dry run: nothing was executed or written.\n\n")
+
+	b.WriteString("Commands:\n")
+	if len(rep.Commands) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for i, c := range rep.Commands {
+		fmt.Fprintf(&b, "  %2d. %s", i+1, strings.Join(c.Argv, " "))
+		var notes []string
+		if c.Dir != "." {
+			notes = append(notes, "in "+c.Dir)
+		}
+		for _, k := range sortedKeys(c.Env) {
+			notes = append(notes, k+"="+c.Env[k])
+		}
+		if c.Interactive {
+			notes = append(notes, "interactive")
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, "  [%s]", strings.Join(notes, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nFiles:\n")
+	if len(rep.Files) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	writeTree(&b, rep.Files)
+
+	for _, f := range rep.Files {
+		if f.Diff == "" {
+			continue
+		}
+		b.WriteString("\n")
+		b.WriteString(f.Diff)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type treeNode struct {
+	name     string
+	action   string
+	children map[string]*treeNode
+}
+
+func writeTree(b *strings.Builder, files []FileReport) {
+	root := &treeNode{children: map[string]*treeNode{}}
+	for _, f := range files {
+		node := root
+		parts := strings.Split(f.Path, "/")
+		for _, part := range parts {
+			child, ok := node.children[part]
+			if !ok {
+				child = &treeNode{name: part, children: map[string]*treeNode{}}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.action = f.Action
+	}
+
+	writeTreeChildren(b, root, "  ")
+}
+
+func writeTreeChildren(b *strings.Builder, node *treeNode, indent string) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := node.children[name]
+		branch, next := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		label := child.name
+		if len(child.children) > 0 && child.action == "" {
+			label += "/"
+		}
+		if child.action != "" {
+			label += " (" + child.action + ")"
+		}
+
+		b.WriteString(indent + branch + label + "\n")
+		writeTreeChildren(b, child, indent+next)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) != -1
+}
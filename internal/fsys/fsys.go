@@ -0,0 +1,80 @@
+// Package fsys routes the file system side effects of go-sparky through a swappable backend,
+// so dry runs and rollbacks can observe or intercept every write.
+package fsys
+
+import "os"
+
+// FS is the set of file operations go-sparky performs on a project.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]os.DirEntry, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	Stat(name string) (os.FileInfo, error)
+	Mkdir(name string, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Remove(name string) error
+	RemoveAll(path string) error
+	Chdir(dir string) error
+}
+
+// OS is the FS backed by the real file system.
+type OS struct{}
+
+func (OS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (OS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+func (OS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+func (OS) Stat(name string) (os.FileInfo, error)        { return os.Stat(name) }
+func (OS) Mkdir(name string, perm os.FileMode) error    { return os.Mkdir(name, perm) }
+func (OS) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+func (OS) Remove(name string) error                     { return os.Remove(name) }
+func (OS) RemoveAll(path string) error                  { return os.RemoveAll(path) }
+func (OS) Chdir(dir string) error                       { return os.Chdir(dir) }
+
+var current FS = OS{}
+
+// Use installs fs as the active backend and returns a function that restores the previous one.
+func Use(fs FS) (restore func()) {
+	prev := current
+	current = fs
+	return func() { current = prev }
+}
+
+// Current returns the active backend.
+func Current() FS { return current }
+
+// ReadFile reads a file through the active backend.
+func ReadFile(name string) ([]byte, error) { return current.ReadFile(name) }
+
+// ReadDir lists a directory, sorted by name, through the active backend.
+func ReadDir(name string) ([]os.DirEntry, error) { return current.ReadDir(name) }
+
+// WriteFile writes a file through the active backend.
+func WriteFile(name string, data []byte, perm os.FileMode) error {
+	return current.WriteFile(name, data, perm)
+}
+
+// Stat describes a file through the active backend.
+func Stat(name string) (os.FileInfo, error) { return current.Stat(name) }
+
+// Mkdir creates a directory through the active backend.
+func Mkdir(name string, perm os.FileMode) error { return current.Mkdir(name, perm) }
+
+// MkdirAll creates a directory tree through the active backend.
+func MkdirAll(path string, perm os.FileMode) error { return current.MkdirAll(path, perm) }
+
+// Remove deletes a file or empty directory through the active backend.
+func Remove(name string) error { return current.Remove(name) }
+
+// RemoveAll deletes a tree through the active backend.
+func RemoveAll(path string) error { return current.RemoveAll(path) }
+
+// Chdir changes the working directory through the active backend.
+func Chdir(dir string) error { return current.Chdir(dir) }
+
+// Exists reports whether name exists according to the active backend.
+func Exists(name string) bool {
+	_, err := current.Stat(name)
+	return err == nil
+}
@@ -1,9 +1,9 @@
 package installer
 
 import (
-	"os"
 	"path/filepath"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/plan"
 	"github.com/hotslug/go-sparky/internal/templates"
 )
@@ -14,12 +14,7 @@ func WriteAppFiles(p plan.Plan) error {
 		return err
 	}
 
-	mainFilename := "main.tsx"
-	if p.IsBun() {
-		mainFilename = "frontend.tsx"
-	}
-
-	if err := WriteMainFile(p, mainFilename); err != nil {
+	if err := WriteMainFile(p, MainEntryFilename(p)); err != nil {
 		return err
 	}
 
@@ -29,11 +24,11 @@ func WriteAppFiles(p plan.Plan) error {
 		}
 	}
 
-	if err := os.MkdirAll(filepath.Join("src", "assets"), 0o755); err != nil {
+	if err := fsys.MkdirAll(filepath.Join("src", "assets"), 0o755); err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(filepath.Join("src", "assets", "sparky.png"), sparkyImage, 0o644); err != nil {
+	if err := writeFile(filepath.Join("src", "assets", "sparky.png"), sparkyImage, 0o644); err != nil {
 		return err
 	}
 
@@ -42,27 +37,27 @@ func WriteAppFiles(p plan.Plan) error {
 		css = tailwindIndexCSS
 	}
 
-	if err := os.WriteFile(filepath.Join("src", "index.css"), []byte(css), 0o644); err != nil {
+	if err := writeFile(filepath.Join("src", "index.css"), []byte(css), 0o644); err != nil {
 		return err
 	}
 
 	if p.IsBun() {
-		if err := os.WriteFile(filepath.Join("src", "index.html"), []byte(templates.BunIndexHTML()), 0o644); err != nil {
+		if err := writeFile(filepath.Join("src", "index.html"), []byte(templates.BunIndexHTML()), 0o644); err != nil {
 			return err
 		}
 	}
 
-	return os.WriteFile(filepath.Join(".", "README.md"), []byte(templates.Readme(p)), 0o644)
+	return writeFile(filepath.Join(".", "README.md"), []byte(templates.Readme(p)), 0o644)
 }
 
 // WriteAppFile writes the App.tsx template based on the plan.
 func WriteAppFile(p plan.Plan) error {
-	return os.WriteFile(filepath.Join("src", "App.tsx"), []byte(templates.AppTemplate(p)), 0o644)
+	return writeFile(filepath.Join("src", "App.tsx"), []byte(templates.AppTemplate(p)), 0o644)
 }
 
 // WriteMainFile writes the main entry template based on the plan.
 func WriteMainFile(p plan.Plan, filename string) error {
-	return os.WriteFile(filepath.Join("src", filename), []byte(templates.MainTemplate(p)), 0o644)
+	return writeFile(filepath.Join("src", filename), []byte(templates.MainTemplate(p)), 0o644)
 }
 
 const baseIndexCSS = `@import url('https://fonts.googleapis.com/css2?family=Fredoka:wght@400;600;700&display=swap');
@@ -3,36 +3,55 @@ package installer
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
-// InstallBulma installs Bulma CSS.
-func InstallBulma(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Bulma")
-	if err := addDependencies(p, false, "bulma@latest"); err != nil {
-		spin("Failed to install Bulma")
-		return err
-	}
-	spin("Installed Bulma")
-	return nil
+var bulmaFeature = &Feature{
+	Name:        "bulma",
+	Title:       "Bulma",
+	FlagUsage:   "Add Bulma CSS (imported at the top of src/index.css)",
+	AddUsage:    "Install Bulma CSS (App.tsx untouched; import it in your CSS)",
+	RemoveUsage: "Uninstall Bulma CSS",
+	Packages:    []Package{{Name: "bulma"}},
+	Selected:    func(p *plan.Plan) *bool { return &p.Bulma },
+	Detect:      func() bool { return hasDependency("bulma") },
+	write: func(plan.Plan) error {
+		return EnsureBulmaImport(filepath.Join("src", "index.css"))
+	},
+	add: addBulmaImport,
+	remove: func(plan.Plan) error {
+		logger.Info("\nBulma removed. App.tsx and CSS files were not modified; remove any Bulma @import you added.")
+		return nil
+	},
 }
 
-// RemoveBulma uninstalls Bulma CSS.
-func RemoveBulma(p plan.Plan) error {
-	spin := logger.StartSpinner("Removing Bulma")
-	if err := removeDependencies(p, false, "bulma"); err != nil {
-		spin("Failed to remove Bulma")
-		return err
+func addBulmaImport(plan.Plan) error {
+	indexCSS := filepath.Join("src", "index.css")
+	if _, err := fsys.Stat(indexCSS); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		logger.Warning("\nBulma installed, but src/index.css was not found; add `@import 'bulma/css/bulma.min.css';` to your global CSS manually.")
+		return nil
 	}
-	spin("Removed Bulma")
+
+	if err := EnsureBulmaImport(indexCSS); err != nil {
+		logger.Warning("\nBulma installed but could not update " + indexCSS + ": " + err.Error())
+		logger.Info("Add `@import 'bulma/css/bulma.min.css';` to your global CSS (after Tailwind directives if you want Tailwind to win). App.tsx left untouched.")
+		return nil
+	}
+
+	logger.Info("\nBulma installed and @import added to src/index.css (placed at the top). App.tsx left untouched.")
 	return nil
 }
 
 // EnsureBulmaImport prepends an @import to the given CSS file if not already present.
 func EnsureBulmaImport(path string) error {
-	data, err := os.ReadFile(path)
+	data, err := fsys.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -42,5 +61,5 @@ func EnsureBulmaImport(path string) error {
 	}
 
 	content := []byte("@import 'bulma/css/bulma.min.css';\n" + string(data))
-	return os.WriteFile(path, content, 0o644)
+	return writeFile(path, content, 0o644)
 }
@@ -3,8 +3,10 @@ package installer
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 	"github.com/hotslug/go-sparky/internal/runner"
@@ -36,56 +38,182 @@ func CleanupBunScaffold() error {
 	}
 
 	for _, file := range filesToRemove {
-		_ = os.RemoveAll(file)
+		_ = fsys.RemoveAll(file)
 	}
 
 	return nil
 }
 
-// WriteBunConfig updates bunfig.toml with plugins needed by the plan.
+// bunTestPreload are the files bun test loads before any test file, in order:
+// happy-dom must provide the DOM before Testing Library is imported.
+var bunTestPreload = []string{"./src/test/happydom.ts", "./src/test/setup.ts"}
+
+// WriteBunConfig updates bunfig.toml with the plugins and test preloads needed by the plan.
 func WriteBunConfig(p plan.Plan) error {
-	if !p.Tailwind {
+	if !p.Tailwind && !p.BunTest {
 		return nil
 	}
 
-	const pluginName = "bun-plugin-tailwind"
-
-	data, err := os.ReadFile("bunfig.toml")
+	data, err := fsys.ReadFile("bunfig.toml")
 	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
-	if strings.Contains(string(data), pluginName) {
+	content := string(data)
+	if p.Tailwind {
+		if content, err = addBunfigItem(content, "serve.static", "plugins", "bun-plugin-tailwind"); err != nil {
+			return err
+		}
+	}
+	if p.BunTest {
+		for _, path := range bunTestPreload {
+			if content, err = addBunfigItem(content, "test", "preload", path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if content == string(data) {
 		return nil
 	}
+	return writeFile("bunfig.toml", []byte(content), 0o644)
+}
 
-	lines := strings.Split(string(data), "\n")
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if !strings.HasPrefix(trimmed, "plugins") {
+// addBunfigItem appends item to the one-line array key of the TOML table,
+// adding the key or the table when they are missing.
+func addBunfigItem(content, table, key, item string) (string, error) {
+	lines := strings.Split(content, "\n")
+	start, end := bunfigTable(lines, table)
+
+	for i := start; i < end; i++ {
+		if !isBunfigKey(lines[i], key) {
 			continue
 		}
 
+		line := lines[i]
 		open := strings.Index(line, "[")
-		close := strings.Index(line, "]")
+		close := strings.LastIndex(line, "]")
 		if open == -1 || close == -1 || close < open {
-			return fmt.Errorf("unsupported bunfig.toml plugins format; add %q manually", pluginName)
+			return "", fmt.Errorf("unsupported bunfig.toml %s format; add %q manually", key, item)
+		}
+		if slices.Contains(bunfigItems(line[open+1:close]), item) {
+			return content, nil
 		}
 
-		contents := strings.TrimSpace(line[open+1 : close])
-		if contents == "" {
-			lines[i] = line[:open+1] + fmt.Sprintf("%q", pluginName) + line[close:]
+		if strings.TrimSpace(line[open+1:close]) == "" {
+			lines[i] = line[:open+1] + fmt.Sprintf("%q", item) + line[close:]
 		} else {
-			lines[i] = line[:close] + ", " + fmt.Sprintf("%q", pluginName) + line[close:]
+			lines[i] = strings.TrimRight(line[:close], " ") + ", " + fmt.Sprintf("%q", item) + line[close:]
 		}
+		return strings.Join(lines, "\n"), nil
+	}
 
-		return os.WriteFile("bunfig.toml", []byte(strings.Join(lines, "\n")), 0o644)
+	entry := key + " = [" + fmt.Sprintf("%q", item) + "]"
+	if start > 0 || table == "" {
+		lines = slices.Insert(lines, start, entry)
+		return strings.Join(lines, "\n"), nil
 	}
 
-	appendLine := "plugins = [" + fmt.Sprintf("%q", pluginName) + "]"
-	if len(data) == 0 {
-		return os.WriteFile("bunfig.toml", []byte(appendLine+"\n"), 0o644)
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		content += "\n\n"
 	}
+	return content + "[" + table + "]\n" + entry + "\n", nil
+}
+
+// removeBunfigItem drops item from the array key of the TOML table, deleting
+// the key once the array is empty and the table once it has no keys left.
+func removeBunfigItem(content, table, key, item string) (string, error) {
+	lines := strings.Split(content, "\n")
+	start, end := bunfigTable(lines, table)
+
+	for i := start; i < end; i++ {
+		if !isBunfigKey(lines[i], key) {
+			continue
+		}
+
+		line := lines[i]
+		open := strings.Index(line, "[")
+		close := strings.LastIndex(line, "]")
+		if open == -1 || close == -1 || close < open {
+			return "", fmt.Errorf("unsupported bunfig.toml %s format; remove %q manually", key, item)
+		}
+
+		var kept []string
+		for _, raw := range strings.Split(line[open+1:close], ",") {
+			if raw = strings.TrimSpace(raw); raw != "" && strings.Trim(raw, `"'`) != item {
+				kept = append(kept, raw)
+			}
+		}
+		if len(kept) > 0 {
+			lines[i] = line[:open+1] + strings.Join(kept, ", ") + line[close:]
+			return strings.Join(lines, "\n"), nil
+		}
 
-	return os.WriteFile("bunfig.toml", []byte(strings.TrimRight(string(data), "\n")+"\n"+appendLine+"\n"), 0o644)
+		lines = slices.Delete(lines, i, i+1)
+		end--
+		if table != "" && start > 0 && bunfigTableEmpty(lines[start:end]) {
+			from := start - 1
+			// The last table also takes the blank lines separating it from the one before.
+			for end == len(lines) && from > 0 && strings.TrimSpace(lines[from-1]) == "" {
+				from--
+			}
+			lines = slices.Delete(lines, from, end)
+		}
+		out := strings.Join(lines, "\n")
+		if strings.HasSuffix(content, "\n") && !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		return out, nil
+	}
+	return content, nil
+}
+
+// bunfigTable returns the line range holding the keys of table; "" is the
+// root table before the first header. A missing table yields an empty range at 0.
+func bunfigTable(lines []string, table string) (start, end int) {
+	start = -1
+	if table == "" {
+		start = 0
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "[") {
+			continue
+		}
+		if start >= 0 {
+			return start, i
+		}
+		if trimmed == "["+table+"]" {
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return 0, 0
+	}
+	return start, len(lines)
+}
+
+func isBunfigKey(line, key string) bool {
+	name, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+	return ok && strings.TrimSpace(name) == key
+}
+
+func bunfigItems(list string) []string {
+	var items []string
+	for _, raw := range strings.Split(list, ",") {
+		if raw = strings.Trim(strings.TrimSpace(raw), `"'`); raw != "" {
+			items = append(items, raw)
+		}
+	}
+	return items
+}
+
+func bunfigTableEmpty(lines []string) bool {
+	for _, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return false
+		}
+	}
+	return true
 }
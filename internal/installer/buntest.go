@@ -0,0 +1,139 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+var bunTestFeature = &Feature{
+	Name:        "bun-test",
+	Title:       "bun test",
+	FlagUsage:   "Add happy-dom + Testing Library for bun test with a starter test (Bun only)",
+	AddUsage:    "Install happy-dom + Testing Library, preload them for bun test and write a starter App test",
+	RemoveUsage: "Uninstall happy-dom + Testing Library and delete the bun test preloads and starter test if unmodified",
+	Bundler:     plan.BundlerBun,
+	Packages: []Package{
+		{Name: "@happy-dom/global-registrator", Dev: true},
+		{Name: "@testing-library/react", Dev: true},
+		{Name: "@testing-library/dom", Dev: true},
+		{Name: "@testing-library/jest-dom", Dev: true},
+		{Name: "@testing-library/user-event", Dev: true},
+	},
+	Files: []string{
+		"src/test/happydom.ts",
+		"src/test/setup.ts",
+		"src/test/matchers.d.ts",
+		"src/App.test.tsx",
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.BunTest },
+	Detect:   HasBunTestDOM,
+	setup:    WriteBunConfig,
+	write: func(p plan.Plan) error {
+		return writeBunTestFiles(templates.AppTest(p))
+	},
+	add:    addBunTest,
+	remove: removeBunTest,
+	verify: verifyBunTest,
+}
+
+// bunTestFiles are the preloads and type declarations bun test needs for component tests.
+var bunTestFiles = []struct{ name, content string }{
+	{"happydom.ts", templates.BunTestHappyDOM},
+	{"setup.ts", templates.BunTestSetup},
+	{"matchers.d.ts", templates.BunTestMatchers},
+}
+
+// HasBunTestDOM reports whether package.json declares the happy-dom registrator bun test preloads.
+func HasBunTestDOM() bool {
+	return hasDependency("@happy-dom/global-registrator")
+}
+
+// writeBunTestFiles writes the bun test preloads and, unless one exists, the starter App test.
+func writeBunTestFiles(appTest string) error {
+	dir := filepath.Join("src", "test")
+	if err := fsys.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range bunTestFiles {
+		if err := writeFile(filepath.Join(dir, f.name), []byte(f.content), 0o644); err != nil {
+			return err
+		}
+	}
+
+	testPath := filepath.Join("src", "App.test.tsx")
+	if fileExists(testPath) {
+		return nil
+	}
+	return writeFile(testPath, []byte(appTest), 0o644)
+}
+
+func addBunTest(plan.Plan) error {
+	if err := writeBunTestFiles(existingAppTest(plan.BundlerBun)); err != nil {
+		return err
+	}
+	if err := WriteBunConfig(plan.Plan{Bundler: plan.BundlerBun, BunTest: true}); err != nil {
+		return err
+	}
+	// Scaffolds declare the test script already; other Bun projects may not.
+	if err := addScripts([]Script{bunTestScript}); err != nil {
+		return err
+	}
+
+	logger.Info("\nComponent tests set up for bun test. Run `bun test`.")
+	return nil
+}
+
+func removeBunTest(plan.Plan) error {
+	dir := filepath.Join("src", "test")
+	for _, f := range bunTestFiles {
+		if err := deleteFileIfContentMatches(filepath.Join(dir, f.name), f.content); err != nil {
+			return err
+		}
+	}
+	if err := removeDirIfEmpty(dir); err != nil {
+		return err
+	}
+	if err := deleteAppTestIfOwned(plan.BundlerBun); err != nil {
+		return err
+	}
+
+	data, err := fsys.ReadFile("bunfig.toml")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	for _, path := range bunTestPreload {
+		if content, err = removeBunfigItem(content, "test", "preload", path); err != nil {
+			return err
+		}
+	}
+	if content == string(data) {
+		return nil
+	}
+	return writeFile("bunfig.toml", []byte(content), 0o644)
+}
+
+// verifyBunTest reports preloads that bunfig.toml no longer loads.
+func verifyBunTest(plan.Plan) []string {
+	data, err := fsys.ReadFile("bunfig.toml")
+	if err != nil {
+		return []string{"happy-dom is installed but bunfig.toml is missing, so bun test has no DOM"}
+	}
+
+	var issues []string
+	for _, path := range bunTestPreload {
+		if !strings.Contains(string(data), path) {
+			issues = append(issues, "happy-dom is installed but bunfig.toml does not preload "+path)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+func TestBunTest_AddRemoveRoundTrips(t *testing.T) {
+	t.Chdir(t.TempDir())
+	bunfig := "[serve.static]\nplugins = [\"bun-plugin-tailwind\"]\nenv = \"BUN_PUBLIC_*\"\n"
+	p := plan.Plan{Bundler: plan.BundlerBun, Zustand: true}
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"bunfig.toml":                   bunfig,
+		"package.json":                  "{\n  \"name\": \"app\"\n}\n",
+		filepath.Join("src", "App.tsx"): templates.AppTemplate(p),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := addBunTest(p); err != nil {
+		t.Fatalf("addBunTest() error = %v", err)
+	}
+
+	wantBunfig := bunfig + "\n[test]\npreload = [\"./src/test/happydom.ts\", \"./src/test/setup.ts\"]\n"
+	if got, _ := os.ReadFile("bunfig.toml"); string(got) != wantBunfig {
+		t.Errorf("bunfig.toml =\n%s\nwant\n%s", got, wantBunfig)
+	}
+	if got, _ := os.ReadFile(filepath.Join("src", "App.test.tsx")); string(got) != templates.AppTest(p) {
+		t.Errorf("App.test.tsx =\n%s", got)
+	}
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := pkg.Script("test"); got != "bun test" {
+		t.Errorf("test script = %q, want bun test", got)
+	}
+
+	// A second run must not duplicate the preloads.
+	if err := WriteBunConfig(plan.Plan{Bundler: plan.BundlerBun, Tailwind: true, BunTest: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile("bunfig.toml"); string(got) != wantBunfig {
+		t.Errorf("bunfig.toml after a second write =\n%s", got)
+	}
+
+	if err := removeBunTest(p); err != nil {
+		t.Fatalf("removeBunTest() error = %v", err)
+	}
+	if got, _ := os.ReadFile("bunfig.toml"); string(got) != bunfig {
+		t.Errorf("bunfig.toml after remove =\n%s\nwant\n%s", got, bunfig)
+	}
+	if fileExists(filepath.Join("src", "test")) || fileExists(filepath.Join("src", "App.test.tsx")) {
+		t.Error("generated test files were kept")
+	}
+}
+
+func TestBunfigItems_KeepOtherTables(t *testing.T) {
+	content := "[test]\ncoverage = true\n\n[serve.static]\nplugins = []\n"
+
+	got, err := addBunfigItem(content, "test", "preload", "./setup.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[test]\npreload = [\"./setup.ts\"]\ncoverage = true\n\n[serve.static]\nplugins = []\n"
+	if got != want {
+		t.Fatalf("addBunfigItem() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, err = removeBunfigItem(got, "test", "preload", "./setup.ts"); err != nil || got != content {
+		t.Fatalf("removeBunfigItem() = %q, %v; want %q", got, err, content)
+	}
+}
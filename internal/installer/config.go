@@ -1,10 +1,6 @@
 package installer
 
-import (
-	"os"
-
-	"github.com/hotslug/go-sparky/internal/plan"
-)
+import "github.com/hotslug/go-sparky/internal/plan"
 
 const postcssConfigContent = `module.exports = {
   plugins: {
@@ -22,17 +18,35 @@ const postcssConfigContent = `module.exports = {
 };
 `
 
-// WriteViteConfig writes vite.config.ts, optionally including the Tailwind plugin.
-func WriteViteConfig(includeTailwind bool) error {
-	content := `import { defineConfig } from "vite";
+// WriteViteConfig writes vite.config.ts with the Tailwind and TanStack Router plugins
+// and the Vitest settings the plan selects.
+func WriteViteConfig(p plan.Plan) error {
+	return writeFile("vite.config.ts", []byte(viteConfigContent(p)), 0o644)
+}
+
+func viteConfigContent(p plan.Plan) string {
+	content := ""
+
+	// Types the test block, which vite's own defineConfig does not know.
+	if p.Vitest {
+		content += `/// <reference types="vitest/config" />
+`
+	}
+
+	content += `import { defineConfig } from "vite";
 import react from "@vitejs/plugin-react";
 `
 
-	if includeTailwind {
+	if p.Tailwind {
 		content += `import tailwindcss from "@tailwindcss/vite";
 `
 	}
 
+	if p.TanStackRouter {
+		content += `import { tanstackRouter } from "@tanstack/router-plugin/vite";
+`
+	}
+
 	content += `import path from "node:path";
 import { fileURLToPath } from "node:url";
 
@@ -40,9 +54,16 @@ const __filename = fileURLToPath(import.meta.url);
 const __dirname = path.dirname(__filename);
 
 export default defineConfig({
-  plugins: [react()`
+  plugins: [`
+
+	// The router plugin generates the route tree, so it runs before react().
+	if p.TanStackRouter {
+		content += tanstackRouterPlugin + ", "
+	}
+
+	content += "react()"
 
-	if includeTailwind {
+	if p.Tailwind {
 		content += ", tailwindcss()"
 	}
 
@@ -52,16 +73,38 @@ export default defineConfig({
       "@": path.resolve(__dirname, "./src"),
     },
   },
-});
 `
 
-	return os.WriteFile("vite.config.ts", []byte(content), 0o644)
+	if p.Vitest {
+		content += vitestTestBlock
+	}
+
+	content += `});
+`
+
+	return content
+}
+
+// generatedViteConfig returns the plugins and test settings a vite config was generated with,
+// or false when the file differs from every generated variant.
+func generatedViteConfig(content string) (plan.Plan, bool) {
+	for _, tailwind := range []bool{false, true} {
+		for _, router := range []bool{false, true} {
+			for _, vitest := range []bool{false, true} {
+				p := plan.Plan{Tailwind: tailwind, TanStackRouter: router, Vitest: vitest}
+				if content == viteConfigContent(p) {
+					return p, true
+				}
+			}
+		}
+	}
+	return plan.Plan{}, false
 }
 
 // WriteConfigFiles writes bundler-specific config files.
 func WriteConfigFiles(p plan.Plan) error {
 	if p.IsVite() {
-		return WriteViteConfig(p.Tailwind)
+		return WriteViteConfig(p)
 	}
 
 	return nil
@@ -69,22 +112,10 @@ func WriteConfigFiles(p plan.Plan) error {
 
 // WritePostCSSConfig writes postcss.config.cjs with a lightweight Mantine preset.
 func WritePostCSSConfig() error {
-	return os.WriteFile("postcss.config.cjs", []byte(postcssConfigContent), 0o644)
+	return writeFile("postcss.config.cjs", []byte(postcssConfigContent), 0o644)
 }
 
 // DeletePostCSSConfigIfOwned deletes postcss.config.cjs when it matches our generated content.
 func DeletePostCSSConfigIfOwned() error {
-	data, err := os.ReadFile("postcss.config.cjs")
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
-	}
-
-	if string(data) != postcssConfigContent {
-		return nil
-	}
-
-	return os.Remove("postcss.config.cjs")
+	return deleteFileIfContentMatches("postcss.config.cjs", postcssConfigContent)
 }
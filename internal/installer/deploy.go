@@ -1,30 +1,76 @@
 package installer
 
 import (
-	"fmt"
-	"os"
-
+	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
+var vercelFeature = &Feature{
+	Name:        "vercel",
+	Title:       "Vercel",
+	FlagUsage:   "Add Vercel static build config",
+	AddUsage:    "Add Vercel static build config",
+	RemoveUsage: "Delete generated vercel.json if unmodified",
+	Files:       []string{"vercel.json"},
+	Selected:    func(p *plan.Plan) *bool { return &p.Vercel },
+	Detect:      func() bool { return fileExists("vercel.json") },
+	write:       WriteVercelConfig,
+	add: func(p plan.Plan) error {
+		if err := WriteVercelConfig(p); err != nil {
+			return err
+		}
+		logger.Info("\nvercel.json written.")
+		return nil
+	},
+	remove: func(plan.Plan) error {
+		if err := DeleteVercelConfig(); err != nil {
+			return err
+		}
+		logger.Info("\nvercel.json removed if it matched the generated content.")
+		return nil
+	},
+}
+
+var netlifyFeature = &Feature{
+	Name:        "netlify",
+	Title:       "Netlify",
+	FlagUsage:   "Add Netlify deploy config",
+	AddUsage:    "Add Netlify deploy config",
+	RemoveUsage: "Delete generated netlify.toml if unmodified",
+	Files:       []string{"netlify.toml"},
+	Selected:    func(p *plan.Plan) *bool { return &p.Netlify },
+	Detect:      func() bool { return fileExists("netlify.toml") },
+	write:       WriteNetlifyConfig,
+	add: func(p plan.Plan) error {
+		if err := WriteNetlifyConfig(p); err != nil {
+			return err
+		}
+		logger.Info("\nnetlify.toml written.")
+		return nil
+	},
+	remove: func(plan.Plan) error {
+		if err := DeleteNetlifyConfig(); err != nil {
+			return err
+		}
+		logger.Info("\nnetlify.toml removed if it matched the generated content.")
+		return nil
+	},
+}
+
 // WriteVercelConfig writes a minimal static build config for Vercel.
 func WriteVercelConfig(p plan.Plan) error {
-	return os.WriteFile("vercel.json", []byte(VercelConfig(p)), 0o644)
+	return writeFile("vercel.json", []byte(VercelConfig(p)), 0o644)
 }
 
 // WriteNetlifyConfig writes a basic Netlify deploy config.
 func WriteNetlifyConfig(p plan.Plan) error {
-	return os.WriteFile("netlify.toml", []byte(NetlifyConfig(p)), 0o644)
+	return writeFile("netlify.toml", []byte(NetlifyConfig(p)), 0o644)
 }
 
 // VercelConfig returns a static build config for the chosen bundler.
 func VercelConfig(p plan.Plan) string {
-	cmd := fmt.Sprintf("%s dev", p.PackageManager())
-	build := fmt.Sprintf("%s build", p.PackageManager())
-	if p.IsBun() {
-		cmd = "bun run dev"
-		build = "bun run build"
-	}
+	cmd := p.PackageManager().Run("dev")
+	build := p.PackageManager().Run("build")
 
 	return `{
   "builds": [
@@ -43,10 +89,7 @@ func VercelConfig(p plan.Plan) string {
 
 // NetlifyConfig returns a Netlify config for the chosen bundler.
 func NetlifyConfig(p plan.Plan) string {
-	build := fmt.Sprintf("%s build", p.PackageManager())
-	if p.IsBun() {
-		build = "bun run build"
-	}
+	build := p.PackageManager().Run("build")
 
 	return `[build]
 command = "` + build + `"
@@ -3,36 +3,66 @@ package installer
 import (
 	"os"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/manifest"
 	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
 )
 
 // DeleteDockerArtifacts deletes Dockerfile and docker-compose.yml if they match generated content.
 func DeleteDockerArtifacts() error {
-	_ = deleteFileIfContentMatches("Dockerfile", dockerfileViteContents, dockerfileBunContents)
-	_ = deleteFileIfContentMatches("docker-compose.yml", dockerComposeViteContents, dockerComposeBunContents)
+	dockerfiles := []string{dockerfileBunContents}
+	composeFiles := []string{dockerComposeBunContents}
+	for _, m := range pm.All() {
+		dockerfiles = append(dockerfiles, dockerfileVite(m))
+		composeFiles = append(composeFiles, dockerComposeVite(m))
+	}
+
+	_ = deleteFileIfContentMatches("Dockerfile", dockerfiles...)
+	_ = deleteFileIfContentMatches("docker-compose.yml", composeFiles...)
 	return nil
 }
 
 // DeleteVercelConfig deletes vercel.json if it matches generated content.
 func DeleteVercelConfig() error {
-	return deleteFileIfContentMatches(
-		"vercel.json",
-		VercelConfig(plan.Plan{Bundler: plan.BundlerVite}),
-		VercelConfig(plan.Plan{Bundler: plan.BundlerBun}),
-	)
+	return deleteFileIfContentMatches("vercel.json", generatedVariants(VercelConfig)...)
 }
 
 // DeleteNetlifyConfig deletes netlify.toml if it matches generated content.
 func DeleteNetlifyConfig() error {
-	return deleteFileIfContentMatches(
-		"netlify.toml",
-		NetlifyConfig(plan.Plan{Bundler: plan.BundlerVite}),
-		NetlifyConfig(plan.Plan{Bundler: plan.BundlerBun}),
-	)
+	return deleteFileIfContentMatches("netlify.toml", generatedVariants(NetlifyConfig)...)
+}
+
+// generatedVariants renders a template for every plan in generatedPlans.
+func generatedVariants(render func(plan.Plan) string) []string {
+	var contents []string
+	for _, p := range generatedPlans() {
+		contents = append(contents, render(p))
+	}
+	return contents
+}
+
+// generatedPlans returns every bundler and package manager pairing, with and
+// without the test stacks that change generated configs.
+func generatedPlans() []plan.Plan {
+	var plans []plan.Plan
+	for _, bundler := range []plan.BundlerType{plan.BundlerVite, plan.BundlerBun} {
+		for _, m := range pm.All() {
+			for _, playwright := range []bool{false, true} {
+				plans = append(plans, plan.Plan{Bundler: bundler, PM: m, Playwright: playwright})
+				if bundler == plan.BundlerVite {
+					plans = append(plans, plan.Plan{Bundler: bundler, PM: m, Vitest: true, Playwright: playwright})
+				}
+			}
+		}
+	}
+	return plans
 }
 
+// deleteFileIfContentMatches deletes path when it matches one of the expected contents,
+// or when .sparky.json shows it is an unmodified generated file.
 func deleteFileIfContentMatches(path string, expected ...string) error {
-	data, err := os.ReadFile(path)
+	data, err := fsys.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -42,9 +72,22 @@ func deleteFileIfContentMatches(path string, expected ...string) error {
 
 	for _, content := range expected {
 		if string(data) == content {
-			return os.Remove(path)
+			return removeFile(path)
 		}
 	}
 
+	if m, err := manifest.Load(); err == nil && m.Pristine(manifestPath(path)) {
+		return removeFile(path)
+	}
+
 	return nil
 }
+
+// removeDirIfEmpty deletes dir once the generated files in it are gone.
+func removeDirIfEmpty(dir string) error {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return nil
+	}
+	return fsys.Remove(dir)
+}
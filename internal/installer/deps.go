@@ -1,8 +1,17 @@
 package installer
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
 	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/registry"
 	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/stack"
 )
 
 func addDependencies(p plan.Plan, dev bool, packages ...string) error {
@@ -10,33 +19,225 @@ func addDependencies(p plan.Plan, dev bool, packages ...string) error {
 		return nil
 	}
 
-	args := []string{}
-	if p.IsBun() {
-		args = append(args, "add")
-		if dev {
-			args = append(args, "-d")
+	m := p.PackageManager()
+	return runner.RunQuiet(m.Bin(), m.AddArgs(dev, packages...)...)
+}
+
+func removeDependencies(p plan.Plan, dev bool, packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	m := p.PackageManager()
+	return runner.RunQuiet(m.Bin(), m.RemoveArgs(dev, packages...)...)
+}
+
+// installPackages installs a feature's dependencies for the plan's bundler behind one spinner.
+func installPackages(f *Feature, p plan.Plan) error {
+	prof := loadStack(p)
+	deps, devDeps := splitPackages(f.PackagesFor(p), func(pkg Package) string { return pkg.Resolve(prof).Spec() })
+	if len(deps) == 0 && len(devDeps) == 0 {
+		return nil
+	}
+
+	spin := logger.StartSpinner("Installing " + f.Title)
+	if err := addDependencies(p, false, deps...); err != nil {
+		spin("Failed to install " + f.Title)
+		return err
+	}
+	if err := addDependencies(p, true, devDeps...); err != nil {
+		spin("Failed to install " + f.Title)
+		return err
+	}
+	spin("Installed " + f.Title)
+	return nil
+}
+
+// uninstallPackages removes a feature's dependencies for the plan's bundler behind one spinner.
+func uninstallPackages(f *Feature, p plan.Plan) error {
+	deps, devDeps := splitPackages(f.PackagesFor(p), func(pkg Package) string { return pkg.Name })
+	leftDeps, leftDevDeps := declaredPackages(f.Leftovers)
+	deps, devDeps = append(deps, leftDeps...), append(devDeps, leftDevDeps...)
+	if len(deps) == 0 && len(devDeps) == 0 {
+		return nil
+	}
+
+	spin := logger.StartSpinner("Removing " + f.Title)
+	if err := removeDependencies(p, false, deps...); err != nil {
+		spin("Failed to remove " + f.Title)
+		return err
+	}
+	if err := removeDependencies(p, true, devDeps...); err != nil {
+		spin("Failed to remove " + f.Title)
+		return err
+	}
+	spin("Removed " + f.Title)
+	return nil
+}
+
+// declaredPackages splits the names package.json declares into dependencies and devDependencies.
+func declaredPackages(names []string) (deps, devDeps []string) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, name := range names {
+		declared, ok := pkg.Dependency(name)
+		switch {
+		case !ok:
+		case declared.Section == pkgjson.DevDependencies:
+			devDeps = append(devDeps, name)
+		default:
+			deps = append(deps, name)
 		}
-	} else {
-		args = append(args, "install")
-		if dev {
-			args = append(args, "-D")
+	}
+	return deps, devDeps
+}
+
+// declaredDependencies returns the dependencies and devDependencies package.json declares.
+func declaredDependencies() []Package {
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		return nil
+	}
+
+	var pkgs []Package
+	for _, name := range pkg.DependencyNames(pkgjson.Dependencies) {
+		pkgs = append(pkgs, Package{Name: name})
+	}
+	for _, name := range pkg.DependencyNames(pkgjson.DevDependencies) {
+		pkgs = append(pkgs, Package{Name: name, Dev: true})
+	}
+	return pkgs
+}
+
+// starterPins returns the starter's dependencies the stack profile pins, such
+// as vite and react, so pinned scaffolds and a .sparky.json passed to --stack
+// reproduce them too.
+func starterPins(prof *stack.Profile) []Package {
+	var pkgs []Package
+	for _, pkg := range declaredDependencies() {
+		if _, ok := prof.Range(pkg.Name); ok {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs
+}
+
+func splitPackages(pkgs []Package, arg func(Package) string) (deps, devDeps []string) {
+	for _, pkg := range pkgs {
+		if pkg.Dev {
+			devDeps = append(devDeps, arg(pkg))
+		} else {
+			deps = append(deps, arg(pkg))
 		}
 	}
+	return deps, devDeps
+}
 
-	args = append(args, packages...)
-	return runner.RunQuiet(p.PackageManager(), args...)
+// loadStack returns the plan's version profile, falling back to the pinned profile
+// when a profile file recorded at scaffold time is no longer readable.
+func loadStack(p plan.Plan) *stack.Profile {
+	prof, err := stack.Load(p.Stack)
+	if err != nil {
+		logger.Warning(err.Error() + "; using the pinned stack")
+		prof, _ = stack.Load(stack.Pinned)
+	}
+	return prof
 }
 
-func removeDependencies(p plan.Plan, dev bool, packages ...string) error {
-	if len(packages) == 0 {
+// mergeDependencies writes every dependency the plan needs into package.json in one pass.
+// Packages the stack profile leaves open are resolved to ^latest up front so the lockfile
+// written by the single install matches package.json.
+func mergeDependencies(p plan.Plan) error {
+	type group struct {
+		title string
+		pkgs  []Package
+	}
+
+	prof := loadStack(p)
+	var groups []group
+	if pkgs := starterPins(prof); len(pkgs) > 0 {
+		groups = append(groups, group{"Starter", pkgs})
+	}
+	if p.IsVite() {
+		groups = append(groups, group{"Vite React plugin", vitePackages})
+	}
+	for _, f := range features {
+		if pkgs := f.PackagesFor(p); f.Enabled(p) && len(pkgs) > 0 {
+			groups = append(groups, group{f.Title, pkgs})
+		}
+	}
+
+	var latest []string
+	for i, g := range groups {
+		resolved := make([]Package, len(g.pkgs))
+		for j, pkg := range g.pkgs {
+			resolved[j] = pkg.Resolve(prof)
+			if resolved[j].Version == "" {
+				latest = append(latest, pkg.Name)
+			}
+		}
+		groups[i].pkgs = resolved
+	}
+
+	versions := map[string]string{}
+	if len(latest) > 0 {
+		spin := logger.StartSpinner("Resolving latest versions")
+		versions = registry.LatestAll(latest)
+		spin(fmt.Sprintf("Resolved %d of %d latest versions", len(versions), len(latest)))
+	}
+
+	return updatePackageJSON(func(pkg *pkgjson.File) error {
+		for _, g := range groups {
+			names := make([]string, 0, len(g.pkgs))
+			for _, dep := range g.pkgs {
+				version := dep.Version
+				if version == "" {
+					version = "latest"
+					if v, ok := versions[dep.Name]; ok {
+						version = "^" + v
+					}
+				}
+				if err := pkg.SetDependency(dep.Name, version, dep.Dev); err != nil {
+					return err
+				}
+				names = append(names, dep.Name+"@"+version)
+			}
+			logger.Step(g.title + ": " + strings.Join(names, ", "))
+		}
 		return nil
+	})
+}
+
+// updatePackageJSON applies edit to package.json and saves it, keeping key order and indentation.
+func updatePackageJSON(edit func(pkg *pkgjson.File) error) error {
+	pkg, err := pkgjson.Load("package.json")
+	if errors.Is(err, fs.ErrNotExist) {
+		// Dry runs never execute the starter that creates package.json.
+		pkg, err = pkgjson.New(), nil
+	}
+	if err != nil {
+		return err
 	}
 
-	args := []string{"remove"}
-	if !p.IsBun() && dev {
-		args = append(args, "-D")
+	if err := edit(pkg); err != nil {
+		return err
 	}
+	return pkg.Save("package.json")
+}
 
-	args = append(args, packages...)
-	return runner.RunQuiet(p.PackageManager(), args...)
+// InstallDependencies runs the one install of a scaffold, after package.json is complete.
+func InstallDependencies(p plan.Plan) error {
+	spin := logger.StartSpinner("Installing dependencies")
+	if err := runner.RunQuiet(p.PackageManager().Bin(), "install"); err != nil {
+		spin("Failed to install dependencies")
+		return err
+	}
+	spin("Installed dependencies")
+	return nil
 }
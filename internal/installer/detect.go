@@ -1,45 +1,33 @@
 package installer
 
 import (
-	"bytes"
+	"encoding/json"
 	"fmt"
-	"os"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/manifest"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
 	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
 )
 
-// HasReactQueryDependency reports whether package.json lists @tanstack/react-query.
+// HasReactQueryDependency reports whether package.json declares @tanstack/react-query.
 // Used to preserve React Query wiring when updating templates on existing projects.
 func HasReactQueryDependency() bool {
-	data, err := os.ReadFile("package.json")
-	if err != nil {
-		return false
-	}
-
-	return bytes.Contains(data, []byte("@tanstack/react-query"))
+	return hasDependency("@tanstack/react-query")
 }
 
-// HasMantineDependency reports whether package.json lists @mantine/core.
+// HasMantineDependency reports whether package.json declares @mantine/core.
 func HasMantineDependency() bool {
-	data, err := os.ReadFile("package.json")
-	if err != nil {
-		return false
-	}
-
-	return bytes.Contains(data, []byte("@mantine/core"))
+	return hasDependency("@mantine/core")
 }
 
-// HasZustandDependency reports whether package.json lists zustand.
+// HasZustandDependency reports whether package.json declares zustand.
 func HasZustandDependency() bool {
-	data, err := os.ReadFile("package.json")
-	if err != nil {
-		return false
-	}
-
-	return bytes.Contains(data, []byte("\"zustand\""))
+	return hasDependency("zustand")
 }
 
-// HasTailwind reports whether tailwindcss appears in package.json or a tailwind config exists.
+// HasTailwind reports whether package.json declares tailwindcss or a tailwind config exists.
 func HasTailwind() bool {
 	if hasTailwindPackage() {
 		return true
@@ -53,7 +41,7 @@ func HasTailwind() bool {
 	}
 
 	for _, cfg := range configs {
-		if _, err := os.Stat(cfg); err == nil {
+		if _, err := fsys.Stat(cfg); err == nil {
 			return true
 		}
 	}
@@ -62,61 +50,116 @@ func HasTailwind() bool {
 }
 
 func hasTailwindPackage() bool {
-	data, err := os.ReadFile("package.json")
-	if err != nil {
-		return false
-	}
-
-	return bytes.Contains(data, []byte("tailwindcss"))
+	return hasDependency("tailwindcss") || hasDependency("@tailwindcss/vite")
 }
 
 // DetectBundler returns the bundler type, preferring explicit markers.
-// Precedence: Vite config > Bun markers > error (no bundler detected).
+// Precedence: .sparky.json > Vite config > Bun markers > error (no bundler detected).
 func DetectBundler() (plan.BundlerType, error) {
-	hasVite := HasViteConfig()
-	hasBun := HasBunProject()
+	bundler, _, err := ExplainBundler()
+	return bundler, err
+}
 
-	if hasVite {
-		return plan.BundlerVite, nil
+// ExplainBundler detects the bundler like DetectBundler and also names the evidence.
+func ExplainBundler() (plan.BundlerType, string, error) {
+	if m, err := manifest.Load(); err == nil && m.Plan.Bundler != "" {
+		return m.Plan.Bundler, "recorded in " + manifest.Filename, nil
 	}
 
-	if hasBun {
-		return plan.BundlerBun, nil
+	if path := viteConfigPath(); path != "" {
+		return plan.BundlerVite, path + " found", nil
 	}
 
-	return "", fmt.Errorf("no bundler detected: run this command from a go-sparky project root")
+	if marker := bunMarker(); marker != "" {
+		return plan.BundlerBun, marker + " found", nil
+	}
+
+	return "", "", fmt.Errorf("no bundler detected: run this command from a go-sparky project root")
 }
 
-// HasBunProject reports whether Bun markers exist in the project.
-func HasBunProject() bool {
-	bunMarkers := []string{
-		"bunfig.toml",
-		"bun.lock",
-		"bun-env.d.ts",
+// ProjectStateFiles lists the files package manager commands rewrite in place.
+func ProjectStateFiles() []string {
+	return append([]string{"package.json", ".yarnrc.yml"}, pm.Lockfiles()...)
+}
+
+// DetectPackageManager returns the project's package manager.
+// Precedence: .sparky.json > lockfile > the bundler's default.
+func DetectPackageManager(bundler plan.BundlerType) pm.Manager {
+	if m, err := manifest.Load(); err == nil && m.Plan.PM != "" {
+		return m.Plan.PM
 	}
 
-	for _, marker := range bunMarkers {
-		if _, err := os.Stat(marker); err == nil {
-			return true
-		}
+	if m, ok := pm.Detect(); ok {
+		return m
 	}
 
-	return false
+	return plan.Plan{Bundler: bundler}.PackageManager()
+}
+
+// RecordedStack returns the stack profile the project was scaffolded with, or "" when unknown.
+func RecordedStack() string {
+	if m, err := manifest.Load(); err == nil {
+		return m.Plan.Stack
+	}
+	return ""
+}
+
+// HasBunProject reports whether Bun markers exist in the project.
+func HasBunProject() bool {
+	return bunMarker() != ""
+}
+
+func bunMarker() string {
+	return existingFile("bunfig.toml", "bun.lock", "bun-env.d.ts")
 }
 
 // HasViteConfig reports whether a Vite config file exists.
 func HasViteConfig() bool {
-	viteConfigs := []string{
-		"vite.config.ts",
-		"vite.config.js",
-		"vite.config.mjs",
+	return viteConfigPath() != ""
+}
+
+func viteConfigPath() string {
+	return existingFile("vite.config.ts", "vite.config.js", "vite.config.mjs")
+}
+
+// hasDependency reports whether a dependency section of package.json declares name.
+func hasDependency(name string) bool {
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		return false
 	}
+	return pkg.HasDependency(name)
+}
 
-	for _, cfg := range viteConfigs {
-		if _, err := os.Stat(cfg); err == nil {
-			return true
+// existingFile returns the first of paths that exists, or "".
+func existingFile(paths ...string) string {
+	for _, path := range paths {
+		if fileExists(path) {
+			return path
 		}
 	}
+	return ""
+}
 
-	return false
+// hasPackageJSONField reports whether package.json has the top-level field key,
+// such as an inline "prettier" or "lint-staged" config.
+func hasPackageJSONField(key string) bool {
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		return false
+	}
+	var raw json.RawMessage
+	ok, _ := pkg.Field(key, &raw)
+	return ok
+}
+
+// missingPackages reports the named packages package.json does not declare.
+func missingPackages(title string, names ...string) []string {
+	var issues []string
+	for _, name := range names {
+		if !hasDependency(name) {
+			issues = append(issues, title+" is configured but "+name+" is not in package.json")
+		}
+	}
+	return issues
 }
@@ -1,42 +1,85 @@
 package installer
 
 import (
-	"os"
+	"strings"
 
+	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
 )
 
+var dockerFeature = &Feature{
+	Name:        "docker",
+	Title:       "Docker",
+	FlagUsage:   "Add Dockerfile and docker-compose.yml",
+	AddUsage:    "Add Dockerfile and docker-compose.yml",
+	RemoveUsage: "Delete generated Docker artifacts",
+	Files:       []string{"Dockerfile", "docker-compose.yml"},
+	Selected:    func(p *plan.Plan) *bool { return &p.Docker },
+	Detect:      func() bool { return fileExists("Dockerfile") },
+	write:       WriteDockerArtifacts,
+	add: func(p plan.Plan) error {
+		if err := WriteDockerArtifacts(p); err != nil {
+			return err
+		}
+		logger.Info("\nDocker artifacts written (Dockerfile, docker-compose.yml).")
+		return nil
+	},
+	remove: func(plan.Plan) error {
+		if err := DeleteDockerArtifacts(); err != nil {
+			return err
+		}
+		logger.Info("\nDocker artifacts removed if they matched the generated content.")
+		return nil
+	},
+}
+
 // WriteDockerArtifacts creates Dockerfile and docker-compose.yml for dev/prod flows.
 func WriteDockerArtifacts(p plan.Plan) error {
-	dockerfileContents := dockerfileViteContents
-	dockerComposeContents := dockerComposeViteContents
+	dockerfileContents := dockerfileVite(p.PackageManager())
+	dockerComposeContents := dockerComposeVite(p.PackageManager())
 	if p.IsBun() {
 		dockerfileContents = dockerfileBunContents
 		dockerComposeContents = dockerComposeBunContents
 	}
 
-	if err := os.WriteFile("Dockerfile", []byte(dockerfileContents), 0o644); err != nil {
+	if err := writeFile("Dockerfile", []byte(dockerfileContents), 0o644); err != nil {
 		return err
 	}
 
-	return os.WriteFile("docker-compose.yml", []byte(dockerComposeContents), 0o644)
+	return writeFile("docker-compose.yml", []byte(dockerComposeContents), 0o644)
 }
 
-const dockerfileViteContents = `# Build static assets
+// dockerfileVite builds the static bundle with the project's package manager and serves it with nginx.
+func dockerfileVite(m pm.Manager) string {
+	setup := ""
+	switch m {
+	case pm.PNPM:
+		setup = "ENV PNPM_HOME=\"/pnpm\"\nENV PATH=\"$PNPM_HOME:$PATH\"\nRUN corepack enable\n"
+	case pm.Yarn, pm.YarnBerry:
+		setup = "RUN corepack enable\n"
+	case pm.Bun:
+		setup = "RUN npm install -g bun\n"
+	}
+
+	manifests := "package.json " + m.Lockfile() + "*"
+	if m == pm.YarnBerry {
+		// Without .yarnrc.yml Berry falls back to Plug'n'Play and creates no node_modules.
+		manifests += " .yarnrc.yml"
+	}
+
+	return `# Build static assets
 FROM node:20-alpine AS base
 WORKDIR /app
-ENV PNPM_HOME="/pnpm"
-ENV PATH="$PNPM_HOME:$PATH"
-RUN corepack enable
-
+` + setup + `
 FROM base AS deps
-COPY package.json pnpm-lock.yaml* ./
-RUN pnpm install --frozen-lockfile
+COPY ` + manifests + ` ./
+RUN ` + m.FrozenInstall() + `
 
 FROM base AS build
 COPY --from=deps /app/node_modules ./node_modules
 COPY . .
-RUN pnpm run build
+RUN ` + m.Bin() + ` run build
 
 # Serve with nginx
 FROM nginx:1.27-alpine AS runner
@@ -44,14 +87,24 @@ COPY --from=build /app/dist /usr/share/nginx/html
 EXPOSE 80
 CMD ["nginx", "-g", "daemon off;"]
 `
+}
 
-const dockerComposeViteContents = `version: "3.9"
+// dockerComposeVite runs the Vite dev server through the project's package manager.
+func dockerComposeVite(m pm.Manager) string {
+	dev := []string{m.Bin(), "run", "dev"}
+	if m == pm.NPM {
+		// npm needs `--` to forward flags to the script.
+		dev = append(dev, "--")
+	}
+	dev = append(dev, "--host", "0.0.0.0", "--port", "5173")
+
+	return `version: "3.9"
 
 services:
   dev:
     image: node:20-alpine
     working_dir: /app
-    command: ["pnpm", "run", "dev", "--host", "0.0.0.0", "--port", "5173"]
+    command: ["` + strings.Join(dev, `", "`) + `"]
     ports:
       - "5173:5173"
     volumes:
@@ -68,6 +121,7 @@ services:
     ports:
       - "4173:80"
 `
+}
 
 const dockerfileBunContents = `# Build static assets
 FROM oven/bun:1 AS build
@@ -0,0 +1,53 @@
+package installer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/pm"
+)
+
+func TestDockerfileVite_YarnBerryCopiesYarnrc(t *testing.T) {
+	got := dockerfileVite(pm.YarnBerry)
+	for _, want := range []string{
+		"RUN corepack enable\n",
+		"COPY package.json yarn.lock* .yarnrc.yml ./\n",
+		"RUN yarn install --immutable\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Dockerfile missing %q:\n%s", want, got)
+		}
+	}
+
+	if classic := dockerfileVite(pm.Yarn); strings.Contains(classic, ".yarnrc.yml") {
+		t.Errorf("Yarn classic Dockerfile copies .yarnrc.yml:\n%s", classic)
+	}
+}
+
+func TestWriteYarnrc_PinsPackageManager(t *testing.T) {
+	t.Chdir(t.TempDir())
+	restore := pm.YarnVersion
+	t.Cleanup(func() { pm.YarnVersion = restore })
+	pm.YarnVersion = func() (string, error) { return "4.9.2", nil }
+
+	if err := os.WriteFile("package.json", []byte("{\n  \"name\": \"app\"\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteYarnrc(); err != nil {
+		t.Fatalf("WriteYarnrc() error = %v", err)
+	}
+
+	if got, _ := os.ReadFile(".yarnrc.yml"); string(got) != "nodeLinker: node-modules\n" {
+		t.Errorf(".yarnrc.yml = %q", got)
+	}
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pinned string
+	if ok, err := pkg.Field("packageManager", &pinned); !ok || err != nil || pinned != "yarn@4.9.2" {
+		t.Errorf("packageManager = %q, %v, %v; want yarn@4.9.2", pinned, ok, err)
+	}
+}
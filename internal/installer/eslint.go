@@ -1,45 +1,149 @@
 package installer
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 	"github.com/hotslug/go-sparky/internal/templates"
 )
 
-// InstallESLint installs ESLint dependencies and config.
-func InstallESLint(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing ESLint")
-	if err := addDependencies(p, true,
-		"eslint@latest",
-		"@eslint/js@latest",
-		"@typescript-eslint/parser@latest",
-		"@typescript-eslint/eslint-plugin@latest",
-		"@tanstack/eslint-plugin-query@latest",
-		"eslint-import-resolver-typescript@latest",
-		"eslint-plugin-react@latest",
-		"eslint-plugin-react-hooks@latest",
-		"eslint-plugin-jsx-a11y@latest",
-		"eslint-plugin-import@latest",
-		"eslint-plugin-unicorn@latest",
-		"eslint-plugin-prettier@latest",
-		"eslint-config-prettier@latest",
-	); err != nil {
-		spin("Failed to install ESLint")
-		return err
-	}
-	spin("Installed ESLint")
-
-	return os.WriteFile("eslint.config.js", []byte(templates.EslintConfig(p)), 0o644)
-}
-
-// WriteESLintStrict rewrites eslint.config.js with the default strict config.
+var eslintFeature = &Feature{
+	Name:        "eslint",
+	Title:       "ESLint",
+	Default:     true,
+	FlagUsage:   "Skip ESLint (default installs)",
+	AddUsage:    "Install ESLint with the strict preset and lint scripts",
+	RemoveUsage: "Uninstall ESLint and delete eslint.config.js if unmodified",
+	Packages: []Package{
+		{Name: "eslint", Dev: true},
+		{Name: "@eslint/js", Dev: true},
+		{Name: "@typescript-eslint/parser", Dev: true},
+		{Name: "@typescript-eslint/eslint-plugin", Dev: true},
+		{Name: "@tanstack/eslint-plugin-query", Dev: true},
+		{Name: "eslint-import-resolver-typescript", Dev: true},
+		{Name: "eslint-plugin-react", Dev: true},
+		{Name: "eslint-plugin-react-hooks", Dev: true},
+		{Name: "eslint-plugin-jsx-a11y", Dev: true},
+		{Name: "eslint-plugin-import", Dev: true},
+		{Name: "eslint-plugin-unicorn", Dev: true},
+		{Name: "eslint-plugin-prettier", Dev: true},
+		{Name: "eslint-config-prettier", Dev: true},
+	},
+	Scripts: []Script{
+		{Name: "lint", Command: "eslint ."},
+		{Name: "lint:fix", Command: "eslint . --fix"},
+	},
+	Files:    []string{"eslint.config.js"},
+	Selected: func(p *plan.Plan) *bool { return &p.Eslint },
+	Detect:   HasESLintConfig,
+	setup:    WriteESLintStrict,
+	check:    checkESLint,
+	add: func(p plan.Plan) error {
+		if err := WriteESLintStrict(p); err != nil {
+			return err
+		}
+		logger.Info("\nESLint added with the strict preset. Run `" + p.PackageManager().Run("lint") + "`; `go-sparky lint relax` loosens it.")
+		return nil
+	},
+	remove: func(plan.Plan) error {
+		return DeleteESLintConfigIfOwned()
+	},
+	verify: func(plan.Plan) []string {
+		return missingPackages("ESLint", "eslint")
+	},
+}
+
+// HasESLintConfig reports whether eslint.config.js exists and is not the stock create-vite config.
+func HasESLintConfig() bool {
+	data, err := fsys.ReadFile("eslint.config.js")
+	return err == nil && !isViteStarterESLintConfig(data)
+}
+
+// WriteESLintStrict rewrites eslint.config.js with the default strict config,
+// declaring test globals when Vitest is installed and ignoring Playwright reports.
 func WriteESLintStrict(p plan.Plan) error {
-	return os.WriteFile("eslint.config.js", []byte(templates.EslintConfig(p)), 0o644)
+	p.Vitest = p.Vitest || (p.IsVite() && HasVitest())
+	p.Playwright = p.Playwright || HasPlaywright()
+	return writeFile("eslint.config.js", []byte(templates.EslintConfig(p)), 0o644)
 }
 
 // WriteESLintRelaxed rewrites eslint.config.js with a looser preset.
 func WriteESLintRelaxed(p plan.Plan) error {
-	return os.WriteFile("eslint.config.js", []byte(templates.EslintConfigRelaxed(p)), 0o644)
+	p.Vitest = p.Vitest || (p.IsVite() && HasVitest())
+	p.Playwright = p.Playwright || HasPlaywright()
+	return writeFile("eslint.config.js", []byte(templates.EslintConfigRelaxed(p)), 0o644)
+}
+
+// DeleteESLintConfigIfOwned deletes eslint.config.js when it matches the strict or relaxed preset.
+func DeleteESLintConfigIfOwned() error {
+	contents := append(generatedVariants(templates.EslintConfig), generatedVariants(templates.EslintConfigRelaxed)...)
+	return deleteFileIfContentMatches("eslint.config.js", contents...)
+}
+
+// rewriteESLintConfig re-renders a generated eslint.config.js after update
+// changes its plan. It reports false, writing nothing, when the file is
+// missing or customized.
+func rewriteESLintConfig(update func(p *plan.Plan)) (bool, error) {
+	data, err := fsys.ReadFile("eslint.config.js")
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, render := range []func(plan.Plan) string{templates.EslintConfig, templates.EslintConfigRelaxed} {
+		for _, p := range generatedPlans() {
+			if string(data) != render(p) {
+				continue
+			}
+			update(&p)
+			if content := render(p); content != string(data) {
+				return true, writeFile("eslint.config.js", []byte(content), 0o644)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkESLint refuses projects that already configure ESLint some other way.
+// The stock config create-vite ships is replaced, since --no-eslint projects keep it.
+func checkESLint(p plan.Plan) (bool, error) {
+	legacy := existingFile(
+		".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml",
+		"eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts", "eslint.config.mts", "eslint.config.cts",
+	)
+	if legacy != "" {
+		return false, fmt.Errorf("%s already configures ESLint; remove it first to use the go-sparky preset", legacy)
+	}
+	if hasPackageJSONField("eslintConfig") {
+		return false, fmt.Errorf("package.json already configures ESLint in \"eslintConfig\"; remove it first to use the go-sparky preset")
+	}
+
+	data, err := fsys.ReadFile("eslint.config.js")
+	if err != nil {
+		return true, nil
+	}
+	for _, content := range append(generatedVariants(templates.EslintConfig), generatedVariants(templates.EslintConfigRelaxed)...) {
+		if string(data) == content {
+			logger.Warning("\neslint.config.js already uses a go-sparky preset; leaving it unchanged.")
+			return false, nil
+		}
+	}
+	if isViteStarterESLintConfig(data) {
+		logger.Info("\nReplacing the create-vite eslint.config.js with the go-sparky strict preset.")
+		return true, nil
+	}
+	return false, fmt.Errorf("eslint.config.js already exists and was not generated by go-sparky; remove it first, or run `go-sparky lint reset` to overwrite it")
+}
+
+// isViteStarterESLintConfig recognizes the flat config create-vite's React templates generate.
+func isViteStarterESLintConfig(data []byte) bool {
+	content := string(data)
+	return strings.Contains(content, "eslint-plugin-react-refresh") && strings.Contains(content, "typescript-eslint")
 }
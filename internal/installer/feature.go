@@ -0,0 +1,501 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/mainfile"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/stack"
+)
+
+// Package is an npm dependency declared by a feature.
+type Package struct {
+	Name    string
+	Version string           // range used when the stack profile has none; empty installs @latest
+	Dev     bool             // install into devDependencies
+	Bundler plan.BundlerType // limits the package to one bundler; empty applies to both
+}
+
+// Script is a package.json script managed by a feature.
+type Script struct {
+	Name    string
+	Command string
+	Bundler plan.BundlerType // limits the script to one bundler; empty applies to both
+}
+
+// Resolve fills in the version range the stack profile pins for the package.
+func (pkg Package) Resolve(prof *stack.Profile) Package {
+	if v, ok := prof.Range(pkg.Name); ok {
+		pkg.Version = v
+	}
+	return pkg
+}
+
+// Spec returns the name@version argument passed to the package manager.
+func (pkg Package) Spec() string {
+	if pkg.Version == "" {
+		return pkg.Name + "@latest"
+	}
+	return pkg.Name + "@" + pkg.Version
+}
+
+// Feature declares everything go-sparky needs to scaffold, add or remove a stack.
+// Commands are generated from the registry, so a new stack only needs a Feature value.
+type Feature struct {
+	Name        string           // CLI name used for scaffold flags and add/remove subcommands
+	Title       string           // human-readable label for spinners and messages
+	Default     bool             // installed on scaffold unless --no-<name> is passed
+	FlagUsage   string           // scaffold flag help; empty when the feature has no scaffold flag
+	AddUsage    string           // `add` help; empty when the feature cannot be added
+	RemoveUsage string           // `remove` help; empty when the feature cannot be removed
+	Bundler     plan.BundlerType // limits the feature to one bundler; empty applies to both
+
+	Packages  []Package
+	Leftovers []string           // packages the feature's own CLI installs; uninstalled on remove when declared
+	Scripts   []Script           // package.json scripts; a user script of the same name is never replaced
+	Files     []string           // files generated by the feature, relative to the project root
+	Provider  *mainfile.Provider // React provider wired into the main entry, if any
+	Requires  []string           // features that must be present first
+	Conflicts []string           // features that cannot be combined with this one
+
+	Selected func(p *plan.Plan) *bool // plan field toggled by the feature; nil for add-only stacks
+	Detect   func() bool              // reports whether the feature is present in the current project
+
+	check     func(p plan.Plan) (bool, error) // runs before `add` installs anything; false skips the feature
+	setup     func(p plan.Plan) error         // runs after dependencies are merged into package.json during scaffolding
+	write     func(p plan.Plan) error         // writes templates after the app files during scaffolding
+	installed func(p plan.Plan) error         // runs after the single install of a scaffold, for steps that need the packages
+	add       func(p plan.Plan) error         // wires the feature into an existing project after install
+	remove    func(p plan.Plan) error         // cleans up generated files after uninstall
+	verify    func(p plan.Plan) []string      // reports inconsistent config of an installed feature for `status`
+}
+
+// features lists every stack in scaffold order.
+var features = []*Feature{
+	mantineFeature,
+	framerMotionFeature,
+	tailwindFeature,
+	reactQueryFeature,
+	zustandFeature,
+	eslintFeature,
+	prettierFeature,
+	huskyFeature,
+	storybookFeature,
+	vitestFeature,
+	bunTestFeature,
+	playwrightFeature,
+	mswFeature,
+	dockerFeature,
+	vercelFeature,
+	netlifyFeature,
+	bulmaFeature,
+	shadcnFeature,
+	reactRouterFeature,
+	tanstackRouterFeature,
+}
+
+// Features returns the registered stacks in scaffold order.
+func Features() []*Feature {
+	return features
+}
+
+// LookupFeature returns the feature registered under name.
+func LookupFeature(name string) (*Feature, bool) {
+	for _, f := range features {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// FlagName returns the scaffold flag for the feature (--<name> or --no-<name>).
+func (f *Feature) FlagName() string {
+	if f.Default {
+		return "no-" + f.Name
+	}
+	return f.Name
+}
+
+// Enabled reports whether the plan selects the feature.
+func (f *Feature) Enabled(p plan.Plan) bool {
+	if f.Selected == nil {
+		return false
+	}
+	return *f.Selected(&p)
+}
+
+// HasPackages reports whether the feature declares npm dependencies for any bundler.
+func (f *Feature) HasPackages() bool {
+	return len(f.Packages) > 0 || len(f.Leftovers) > 0
+}
+
+// PackagesFor returns the feature's dependencies for the plan's bundler.
+func (f *Feature) PackagesFor(p plan.Plan) []Package {
+	var pkgs []Package
+	for _, pkg := range f.Packages {
+		if pkg.Bundler != "" && pkg.Bundler != p.Bundler {
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
+
+// ScriptsFor returns the feature's package.json scripts for the plan's bundler.
+func (f *Feature) ScriptsFor(p plan.Plan) []Script {
+	return scriptsFor(f.Scripts, p)
+}
+
+// checkBundler reports a feature limited to another bundler than the plan's.
+func (f *Feature) checkBundler(p plan.Plan) error {
+	if f.Bundler == "" || f.Bundler == p.Bundler {
+		return nil
+	}
+	bundler := "Vite"
+	if f.Bundler == plan.BundlerBun {
+		bundler = "Bun"
+	}
+	return fmt.Errorf("%s is only available for %s projects", f.Title, bundler)
+}
+
+// ValidatePlan checks prerequisites and conflicts between the features a plan selects.
+func ValidatePlan(p plan.Plan) error {
+	for _, f := range features {
+		if !f.Enabled(p) {
+			continue
+		}
+
+		if err := f.checkBundler(p); err != nil {
+			return err
+		}
+
+		for _, name := range f.Requires {
+			if dep, ok := LookupFeature(name); ok && dep.Selected != nil && !dep.Enabled(p) {
+				return fmt.Errorf("%s requires %s; drop --%s or enable %s", f.Title, dep.Title, f.FlagName(), dep.Title)
+			}
+		}
+
+		for _, name := range f.Conflicts {
+			if other, ok := LookupFeature(name); ok && other.Enabled(p) {
+				return fmt.Errorf("%s cannot be combined with %s", f.Title, other.Title)
+			}
+		}
+	}
+
+	return nil
+}
+
+// InstallFeatures declares the dependencies and scripts of every feature the plan
+// selects in package.json and runs their setup. Nothing is installed until InstallDependencies,
+// so a scaffold resolves the dependency tree once.
+func InstallFeatures(p plan.Plan) error {
+	if err := mergeDependencies(p); err != nil {
+		return err
+	}
+	if err := mergeScripts(p); err != nil {
+		return err
+	}
+
+	for _, f := range features {
+		if !f.Enabled(p) {
+			continue
+		}
+
+		if f.setup != nil {
+			if err := f.setup(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteFeatureFiles writes the templates of every selected feature once the app files exist.
+func WriteFeatureFiles(p plan.Plan) error {
+	for _, f := range features {
+		if !f.Enabled(p) || f.write == nil {
+			continue
+		}
+
+		if err := f.write(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FinishFeatures runs the post-install steps of every selected feature once the
+// scaffold's dependencies are installed.
+func FinishFeatures(p plan.Plan) error {
+	for _, f := range features {
+		if !f.Enabled(p) || f.installed == nil {
+			continue
+		}
+
+		if err := f.installed(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddFeature installs a feature into the existing project in the current directory.
+func AddFeature(f *Feature, p plan.Plan) error {
+	if f.HasPackages() {
+		if err := requirePackageJSON(); err != nil {
+			return err
+		}
+	}
+
+	if err := f.checkBundler(p); err != nil {
+		return err
+	}
+
+	for _, name := range f.Requires {
+		dep, ok := LookupFeature(name)
+		if ok && dep.Detect != nil && !dep.Detect() {
+			return fmt.Errorf("%s not detected. %s requires %s; install %s first", dep.Title, f.Title, dep.Title, dep.Title)
+		}
+	}
+
+	for _, name := range f.Conflicts {
+		other, ok := LookupFeature(name)
+		if ok && other.Detect != nil && other.Detect() {
+			return fmt.Errorf("%s is already installed and cannot be combined with %s", other.Title, f.Title)
+		}
+	}
+
+	if f.Provider != nil {
+		if _, err := readMainEntry(p); err != nil {
+			return err
+		}
+	}
+
+	if f.check != nil {
+		ok, err := f.check(p)
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	if f.Selected != nil {
+		*f.Selected(&p) = true
+	}
+
+	if err := installPackages(f, p); err != nil {
+		return err
+	}
+	if err := addScripts(f.ScriptsFor(p)); err != nil {
+		return err
+	}
+
+	if f.add != nil {
+		if err := f.add(p); err != nil {
+			return err
+		}
+	}
+
+	if f.Provider != nil {
+		if err := wireProvider(f, p, true); err != nil {
+			return err
+		}
+	} else if f.add == nil {
+		logger.Info("\n" + f.Title + " installed. App.tsx left untouched.")
+	}
+
+	return updateManifest(f, p, true)
+}
+
+// RemoveFeature uninstalls a feature from the existing project in the current directory.
+func RemoveFeature(f *Feature, p plan.Plan) error {
+	if f.HasPackages() {
+		if err := requirePackageJSON(); err != nil {
+			return err
+		}
+	}
+
+	for _, other := range features {
+		if other == f || other.Detect == nil || !slices.Contains(other.Requires, f.Name) {
+			continue
+		}
+		if other.Detect() {
+			return fmt.Errorf("%s requires %s; remove %s first", other.Title, f.Title, other.Name)
+		}
+	}
+
+	if f.Provider != nil {
+		if _, err := readMainEntry(p); err != nil {
+			return err
+		}
+	}
+
+	if f.Selected != nil {
+		*f.Selected(&p) = false
+	}
+
+	if err := uninstallPackages(f, p); err != nil {
+		return err
+	}
+	if err := removeScripts(f.ScriptsFor(p)); err != nil {
+		return err
+	}
+
+	if f.remove != nil {
+		if err := f.remove(p); err != nil {
+			return err
+		}
+	}
+
+	if f.Provider != nil {
+		if err := wireProvider(f, p, false); err != nil {
+			return err
+		}
+	} else if f.remove == nil {
+		logger.Info("\n" + f.Title + " removed. App.tsx left untouched.")
+	}
+
+	return updateManifest(f, p, false)
+}
+
+// wireProvider adds or removes the feature's provider in the main entry in place,
+// leaving the rest of the file (other providers, routers, setup code) untouched.
+// Entry files too unusual to edit are left alone with instructions instead.
+func wireProvider(f *Feature, p plan.Plan, enable bool) error {
+	mainPath := filepath.Join("src", MainEntryFilename(p))
+	mainContent, err := readMainEntry(p)
+	if err != nil {
+		return err
+	}
+
+	has, err := mainfile.Has(mainContent, f.Provider)
+	if err == nil && has == enable {
+		if enable {
+			logger.Info("\n" + f.Provider.Element + " already detected in " + mainPath + "; leaving the file unchanged.")
+			logger.Info("\n" + f.Title + " packages installed. App.tsx was not modified.")
+		} else {
+			logger.Info("\n" + f.Provider.Element + " not found in " + mainPath + "; leaving the file unchanged.")
+			logger.Info("\n" + f.Title + " packages removed. App.tsx was not modified.")
+		}
+		return nil
+	}
+
+	var updated []byte
+	if err == nil {
+		if enable {
+			updated, err = mainfile.Insert(mainContent, f.Provider)
+		} else {
+			updated, err = mainfile.Remove(mainContent, f.Provider)
+		}
+	}
+	if errors.Is(err, mainfile.ErrUnsupported) {
+		logger.Warning("\nCould not update " + mainPath + " automatically (" + err.Error() + "); leaving the file unchanged.")
+		logger.Info(manualProviderSteps(f, mainPath, enable))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(mainPath, updated, 0o644); err != nil {
+		return err
+	}
+
+	if enable {
+		logger.Info("\n" + f.Title + " added. " + mainPath + " updated with " + f.Provider.Element + ". App.tsx left untouched.")
+	} else {
+		logger.Info("\n" + f.Title + " removed. " + mainPath + " updated to remove " + f.Provider.Element + ". App.tsx left untouched.")
+	}
+	return nil
+}
+
+// manualProviderSteps describes the edit wireProvider could not make.
+func manualProviderSteps(f *Feature, mainPath string, enable bool) string {
+	var b strings.Builder
+	if enable {
+		b.WriteString("\nTo finish setting up " + f.Title + ", edit " + mainPath + ":")
+		for _, imp := range f.Provider.Imports {
+			b.WriteString("\n  - import { " + strings.Join(imp.Names, ", ") + " } from '" + imp.Module + "';")
+		}
+		if f.Provider.Setup != "" {
+			b.WriteString("\n  - add " + f.Provider.Setup)
+		}
+		open := "<" + f.Provider.Element
+		if f.Provider.Attrs != "" {
+			open += " " + f.Provider.Attrs
+		}
+		if f.Provider.Replaces != "" {
+			b.WriteString("\n  - render " + open + " /> in place of <" + f.Provider.Replaces + " />")
+			return b.String()
+		}
+		b.WriteString("\n  - wrap your app in " + open + ">...</" + f.Provider.Element + ">")
+		for _, extra := range f.Provider.Extras {
+			b.WriteString("\n  - render " + extra + " inside it")
+		}
+		return b.String()
+	}
+
+	b.WriteString("\nTo finish removing " + f.Title + ", edit " + mainPath + ":")
+	if f.Provider.Replaces != "" {
+		imp := f.Provider.ReplacesImport
+		b.WriteString("\n  - render <" + f.Provider.Replaces + " /> in place of <" + f.Provider.Element + " />")
+		b.WriteString("\n  - import " + imp.Default + " from '" + imp.Module + "';")
+	} else {
+		b.WriteString("\n  - unwrap <" + f.Provider.Element + "> and keep its children")
+	}
+	for _, extra := range f.Provider.Extras {
+		b.WriteString("\n  - delete " + extra)
+	}
+	if f.Provider.Setup != "" {
+		b.WriteString("\n  - delete " + f.Provider.Setup)
+	}
+	for _, imp := range f.Provider.Imports {
+		b.WriteString("\n  - drop the imports from '" + imp.Module + "'")
+	}
+	return b.String()
+}
+
+// MainEntryFilename returns the React entry file under src/ for the bundler.
+func MainEntryFilename(p plan.Plan) string {
+	if p.IsBun() {
+		return "frontend.tsx"
+	}
+	return "main.tsx"
+}
+
+func readMainEntry(p plan.Plan) ([]byte, error) {
+	mainPath := filepath.Join("src", MainEntryFilename(p))
+	data, err := fsys.ReadFile(mainPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s not found. Run this in a go-sparky project", mainPath)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func requirePackageJSON() error {
+	if _, err := fsys.Stat("package.json"); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("package.json not found. Run this inside your existing app directory")
+		}
+		return err
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := fsys.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,153 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/dryrun"
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/mainfile"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/stack"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+func TestFeatureRegistry_UniqueNamesAndFlags(t *testing.T) {
+	seen := map[string]bool{}
+	for _, f := range Features() {
+		if f.Name == "" || f.Title == "" {
+			t.Fatalf("feature %+v is missing a name or title", f)
+		}
+		if seen[f.Name] {
+			t.Fatalf("duplicate feature %q", f.Name)
+		}
+		seen[f.Name] = true
+
+		if f.FlagUsage != "" && f.Selected == nil {
+			t.Fatalf("feature %q exposes a scaffold flag but no plan field", f.Name)
+		}
+		for _, dep := range append(f.Requires, f.Conflicts...) {
+			if _, ok := LookupFeature(dep); !ok {
+				t.Fatalf("feature %q references unknown feature %q", f.Name, dep)
+			}
+		}
+	}
+}
+
+func TestPackagesFor_FiltersByBundler(t *testing.T) {
+	vite := tailwindFeature.PackagesFor(plan.Plan{Bundler: plan.BundlerVite})
+	bun := tailwindFeature.PackagesFor(plan.Plan{Bundler: plan.BundlerBun})
+
+	if len(vite) != 2 || vite[1].Name != "@tailwindcss/vite" {
+		t.Fatalf("unexpected Vite Tailwind packages: %+v", vite)
+	}
+	if len(bun) != 2 || bun[0].Spec() != "tailwindcss@^4" || bun[1].Name != "bun-plugin-tailwind" {
+		t.Fatalf("unexpected Bun Tailwind packages: %+v", bun)
+	}
+}
+
+func TestPinnedStack_CoversEveryPackage(t *testing.T) {
+	prof, err := stack.Load(stack.Pinned)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgs := append([]Package{{Name: "create-vite"}, {Name: "vite"}, {Name: "react"}, {Name: "react-dom"}}, vitePackages...)
+	for _, f := range Features() {
+		pkgs = append(pkgs, f.Packages...)
+	}
+
+	for _, pkg := range pkgs {
+		if _, ok := prof.Range(pkg.Name); !ok {
+			t.Errorf("pinned stack has no range for %s", pkg.Name)
+		}
+	}
+
+	if got := (Package{Name: "zustand"}).Resolve(prof).Spec(); got != "zustand@"+prof.Packages["zustand"] {
+		t.Errorf("Resolve(pinned).Spec() = %q", got)
+	}
+
+	latest, err := stack.Load(stack.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (Package{Name: "zustand"}).Resolve(latest).Spec(); got != "zustand@latest" {
+		t.Errorf("Resolve(latest).Spec() = %q, want zustand@latest", got)
+	}
+}
+
+func TestSetupHusky_NoGitWritesHooksWithoutCommands(t *testing.T) {
+	t.Chdir(t.TempDir())
+	rec := dryrun.NewRecorder()
+	defer fsys.Use(rec)()
+	defer runner.Use(rec)()
+
+	if err := setupHusky(plan.Plan{Bundler: plan.BundlerVite, NoGit: true}); err != nil {
+		t.Fatalf("setupHusky() error = %v", err)
+	}
+
+	report := rec.Report()
+	if len(report.Commands) != 0 {
+		t.Fatalf("setupHusky() ran %v, want no commands without git", report.Commands)
+	}
+	written := map[string]bool{}
+	for _, f := range report.Files {
+		written[f.Path] = true
+	}
+	if !written[".lintstagedrc"] || !written[".husky/pre-commit"] {
+		t.Fatalf("setupHusky() wrote %v, want .lintstagedrc and .husky/pre-commit", report.Files)
+	}
+}
+
+func TestProviders_MatchMainTemplate(t *testing.T) {
+	main := func(mantine, query bool) string {
+		return templates.MainTemplate(plan.Plan{Mantine: mantine, ReactQuery: query})
+	}
+	routed := func(p plan.Plan) string {
+		return templates.MainTemplate(p)
+	}
+	reactRouter, tanstackRouter := reactRouterFeature.Provider, tanstackRouterFeature.Provider
+	steps := []struct {
+		name     string
+		provider *mainfile.Provider
+		insert   bool
+		from, to string
+	}{
+		{"add query", queryProvider, true, main(false, false), main(false, true)},
+		{"add mantine", mantineProvider, true, main(false, false), main(true, false)},
+		{"add mantine inside query", mantineProvider, true, main(false, true), main(true, true)},
+		{"add query around mantine", queryProvider, true, main(true, false), main(true, true)},
+		{"remove query", queryProvider, false, main(false, true), main(false, false)},
+		{"remove mantine", mantineProvider, false, main(true, false), main(false, false)},
+		{"remove mantine keeps query", mantineProvider, false, main(true, true), main(false, true)},
+		{"remove query keeps mantine", queryProvider, false, main(true, true), main(true, false)},
+		{"add react router", reactRouter, true, main(false, false), routed(plan.Plan{ReactRouter: true})},
+		{"add react router inside providers", reactRouter, true, main(true, true), routed(plan.Plan{Mantine: true, ReactQuery: true, ReactRouter: true})},
+		{"add tanstack router", tanstackRouter, true, main(false, false), routed(plan.Plan{TanStackRouter: true})},
+		{"add tanstack router inside query", tanstackRouter, true, main(false, true), routed(plan.Plan{ReactQuery: true, TanStackRouter: true})},
+		{"add mantine around router", mantineProvider, true, routed(plan.Plan{ReactRouter: true}), routed(plan.Plan{Mantine: true, ReactRouter: true})},
+		{"add query around router", queryProvider, true, routed(plan.Plan{Mantine: true, TanStackRouter: true}), routed(plan.Plan{Mantine: true, ReactQuery: true, TanStackRouter: true})},
+		{"remove react router", reactRouter, false, routed(plan.Plan{ReactRouter: true}), main(false, false)},
+		{"remove tanstack router keeps providers", tanstackRouter, false, routed(plan.Plan{Mantine: true, ReactQuery: true, TanStackRouter: true}), main(true, true)},
+		{"remove mantine keeps router", mantineProvider, false, routed(plan.Plan{Mantine: true, ReactRouter: true}), routed(plan.Plan{ReactRouter: true})},
+		{"remove query keeps router", queryProvider, false, routed(plan.Plan{ReactQuery: true, TanStackRouter: true}), routed(plan.Plan{TanStackRouter: true})},
+		{"add query with msw", queryProvider, true, routed(plan.Plan{MSW: true}), routed(plan.Plan{ReactQuery: true, MSW: true})},
+		{"remove query keeps msw", queryProvider, false, routed(plan.Plan{Mantine: true, ReactQuery: true, MSW: true}), routed(plan.Plan{Mantine: true, MSW: true})},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			edit := mainfile.Remove
+			if step.insert {
+				edit = mainfile.Insert
+			}
+			got, err := edit([]byte(step.from), step.provider)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if string(got) != step.to {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, step.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// changes tracks the files the current command wrote or deleted so the manifest can record them.
+var changes = struct {
+	written map[string]bool
+	removed map[string]bool
+}{
+	written: map[string]bool{},
+	removed: map[string]bool{},
+}
+
+// writeFile writes a generated file and remembers it for the manifest.
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	if err := fsys.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+
+	key := manifestPath(path)
+	changes.written[key] = true
+	delete(changes.removed, key)
+	return nil
+}
+
+// removeFile deletes a generated file and remembers it for the manifest.
+func removeFile(path string) error {
+	if err := fsys.Remove(path); err != nil {
+		return err
+	}
+
+	key := manifestPath(path)
+	changes.removed[key] = true
+	delete(changes.written, key)
+	return nil
+}
+
+// manifestPath normalizes a project-relative path to the form stored in .sparky.json.
+func manifestPath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// trackFile records a file written by an external tool, such as shadcn-ui init,
+// so the manifest can tell later whether it was edited.
+func trackFile(path string) {
+	if _, err := fsys.Stat(path); err != nil {
+		return
+	}
+	key := manifestPath(path)
+	changes.written[key] = true
+	delete(changes.removed, key)
+}
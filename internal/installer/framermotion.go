@@ -1,28 +1,15 @@
 package installer
 
-import (
-	"github.com/hotslug/go-sparky/internal/logger"
-	"github.com/hotslug/go-sparky/internal/plan"
-)
+import "github.com/hotslug/go-sparky/internal/plan"
 
-// InstallFramerMotion installs Framer Motion dependency.
-func InstallFramerMotion(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Framer Motion")
-	if err := addDependencies(p, false, "framer-motion@latest"); err != nil {
-		spin("Failed to install Framer Motion")
-		return err
-	}
-	spin("Installed Framer Motion")
-	return nil
-}
-
-// RemoveFramerMotion uninstalls Framer Motion.
-func RemoveFramerMotion(p plan.Plan) error {
-	spin := logger.StartSpinner("Removing Framer Motion")
-	if err := removeDependencies(p, false, "framer-motion"); err != nil {
-		spin("Failed to remove Framer Motion")
-		return err
-	}
-	spin("Removed Framer Motion")
-	return nil
+var framerMotionFeature = &Feature{
+	Name:        "framer-motion",
+	Title:       "Framer Motion",
+	Default:     true,
+	FlagUsage:   "Skip Framer Motion (default installs)",
+	AddUsage:    "Install Framer Motion",
+	RemoveUsage: "Uninstall Framer Motion",
+	Packages:    []Package{{Name: "framer-motion"}},
+	Selected:    func(p *plan.Plan) *bool { return &p.Framer },
+	Detect:      func() bool { return hasDependency("framer-motion") },
 }
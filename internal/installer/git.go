@@ -1,17 +1,18 @@
 package installer
 
 import (
-    "fmt"
-    "os"
-    "os/exec"
+	"fmt"
+	"os"
 
-    "github.com/hotslug/go-sparky/internal/logger"
-    "github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/runner"
 )
 
 // CreateInitialCommitIfMissing commits the current workspace if no commits exist yet.
-func CreateInitialCommitIfMissing(message string) error {
-	if _, err := os.Stat(".git"); err != nil {
+// With verify false the commit skips hooks, whose tools may not be installed.
+func CreateInitialCommitIfMissing(message string, verify bool) error {
+	if _, err := fsys.Stat(".git"); err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
@@ -19,7 +20,9 @@ func CreateInitialCommitIfMissing(message string) error {
 	}
 
 	// If a commit already exists, skip.
-	if err := exec.Command("git", "rev-parse", "--verify", "HEAD").Run(); err == nil {
+	if ok, err := runner.Succeeds("git", "rev-parse", "--verify", "HEAD"); err != nil {
+		return err
+	} else if ok {
 		return nil
 	}
 
@@ -30,13 +33,17 @@ func CreateInitialCommitIfMissing(message string) error {
 	}
 
 	// If nothing was staged, exit quietly.
-	if err := exec.Command("git", "diff", "--cached", "--quiet").Run(); err == nil {
-		return nil
-	} else if _, ok := err.(*exec.ExitError); !ok {
+	if clean, err := runner.Succeeds("git", "diff", "--cached", "--quiet"); err != nil {
 		return err
+	} else if clean {
+		return nil
 	}
 
-	if err := runner.RunQuiet("git", "commit", "-m", message); err != nil {
+	args := []string{"commit", "-m", message}
+	if !verify {
+		args = append(args, "--no-verify")
+	}
+	if err := runner.RunQuiet("git", args...); err != nil {
 		return fmt.Errorf("Initial git commit failed (check hook output above or rerun with --no-husky): %w", err)
 	}
 
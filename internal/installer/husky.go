@@ -1,18 +1,91 @@
 package installer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
 	"github.com/hotslug/go-sparky/internal/plan"
 	"github.com/hotslug/go-sparky/internal/runner"
 	"github.com/hotslug/go-sparky/internal/templates"
 )
 
-// InstallHusky installs Husky, initializes hooks, and writes lint-staged config.
-func InstallHusky(p plan.Plan) error {
-	if _, err := os.Stat(".git"); err != nil {
+var huskyFeature = &Feature{
+	Name:        "husky",
+	Title:       "Husky and lint-staged",
+	Default:     true,
+	FlagUsage:   "Skip Husky + lint-staged (default installs)",
+	AddUsage:    "Install Husky and lint-staged with a pre-commit hook",
+	RemoveUsage: "Uninstall Husky and lint-staged, unhook git, and delete the generated hook and config if unmodified",
+	Packages: []Package{
+		{Name: "husky", Dev: true},
+		{Name: "lint-staged", Dev: true},
+	},
+	Files:    []string{".lintstagedrc", ".husky/pre-commit"},
+	Selected: func(p *plan.Plan) *bool { return &p.Husky },
+	Detect:   func() bool { return fileExists(".husky") },
+	setup:    setupHusky,
+	check:    checkHusky,
+	add:      addHusky,
+	remove:   removeHusky,
+	verify: func(plan.Plan) []string {
+		var issues []string
+		for _, path := range []string{".lintstagedrc", filepath.Join(".husky", "pre-commit")} {
+			if !fileExists(path) {
+				issues = append(issues, "Husky is installed but "+path+" is missing")
+			}
+		}
+		return append(issues, missingPackages("Husky", "husky", "lint-staged")...)
+	},
+}
+
+// addHusky sets up hooks in an existing project. Inside a monorepo package the
+// enclosing repository owns the hooks, so only the config files are written.
+func addHusky(p plan.Plan) error {
+	p.NoGit = InEnclosingRepo(".")
+	if err := setupHusky(p); err != nil {
+		return err
+	}
+	logger.Info("\nHusky and lint-staged added. Staged files are linted and formatted on commit.")
+	return nil
+}
+
+// checkHusky refuses projects that already manage git hooks or lint-staged another way.
+func checkHusky(plan.Plan) (bool, error) {
+	if fileExists(".husky") {
+		logger.Warning("\n.husky already exists; leaving your hooks unchanged.")
+		return false, nil
+	}
+
+	other := existingFile(
+		".lintstagedrc", ".lintstagedrc.json", ".lintstagedrc.yml", ".lintstagedrc.yaml",
+		".lintstagedrc.js", ".lintstagedrc.cjs", ".lintstagedrc.mjs",
+		"lint-staged.config.js", "lint-staged.config.cjs", "lint-staged.config.mjs",
+		"lefthook.yml", ".lefthook.yml", ".pre-commit-config.yaml",
+	)
+	if other != "" {
+		return false, fmt.Errorf("%s already manages commit hooks or lint-staged; remove it first to use Husky", other)
+	}
+	for _, field := range []string{"lint-staged", "simple-git-hooks"} {
+		if hasPackageJSONField(field) {
+			return false, fmt.Errorf("package.json already configures %q; remove it first to use Husky", field)
+		}
+	}
+	return true, nil
+}
+
+// setupHusky initializes git and Husky hooks, then writes the lint-staged config.
+// Without git, only the config files are written; the hooks are installed later by husky-init.
+func setupHusky(p plan.Plan) error {
+	if p.NoGit {
+		logger.Warning("Husky needs a git repository; skipping hook installation. Run `" + p.PackageManager().Dlx("husky-init") + "` after `git init`.")
+		return writeHuskyFiles(p)
+	}
+
+	if _, err := fsys.Stat(".git"); err != nil {
 		if os.IsNotExist(err) {
 			spin := logger.StartSpinner("Initializing git repository")
 			if err := runner.RunQuiet("git", "init", "-b", "main"); err != nil {
@@ -25,37 +98,63 @@ func InstallHusky(p plan.Plan) error {
 		}
 	}
 
-	spin := logger.StartSpinner("Installing Husky and lint-staged")
-	packages := []string{"husky@latest", "lint-staged@latest"}
-	if p.IsBun() {
-		packages = append(packages, "husky-init@latest")
+	// husky-init runs before the single install, so it is fetched on demand rather than installed.
+	spin := logger.StartSpinner("Initializing Husky")
+	bin, args := p.PackageManager().DlxArgs("husky-init", "--no-install")
+	if err := runner.RunQuiet(bin, args...); err != nil {
+		spin("Failed to initialize Husky")
+		return err
+	}
+	spin("Initialized Husky")
+
+	return writeHuskyFiles(p)
+}
+
+// writeHuskyFiles writes the lint-staged config and the pre-commit hook.
+func writeHuskyFiles(p plan.Plan) error {
+	if err := writeFile(".lintstagedrc", []byte(templates.LintStagedConfig(p)), 0o644); err != nil {
+		return err
 	}
 
-	if err := addDependencies(p, true, packages...); err != nil {
-		spin("Failed to install Husky and lint-staged")
+	if err := fsys.MkdirAll(".husky", 0o755); err != nil {
 		return err
 	}
 
-	if p.IsBun() {
-		if err := runner.RunQuiet("bun", "run", "husky-init", "--", "--no-install"); err != nil {
-			spin("Failed to initialize Husky")
-			return err
-		}
-	} else {
-		if err := runner.RunQuiet("pnpm", "dlx", "husky-init", "--no-install"); err != nil {
-			spin("Failed to initialize Husky")
-			return err
-		}
+	return writeFile(filepath.Join(".husky", "pre-commit"), []byte(templates.HuskyPreCommit(p)), 0o755)
+}
+
+// removeHusky deletes the generated hook and lint-staged config, the prepare
+// script husky-init added, and points git back at its default hooks.
+func removeHusky(plan.Plan) error {
+	if err := deleteFileIfContentMatches(".lintstagedrc", generatedVariants(templates.LintStagedConfig)...); err != nil {
+		return err
+	}
+	if err := deleteFileIfContentMatches(filepath.Join(".husky", "pre-commit"), generatedVariants(templates.HuskyPreCommit)...); err != nil {
+		return err
 	}
-	spin("Installed Husky and lint-staged")
 
-	if err := os.WriteFile(".lintstagedrc", []byte(templates.LintStagedConfig(p)), 0o644); err != nil {
+	// husky-init's helper directory goes too once no hooks of yours remain.
+	if entries, err := fsys.ReadDir(".husky"); err == nil && len(entries) == 1 && entries[0].Name() == "_" {
+		if err := fsys.RemoveAll(".husky"); err != nil {
+			return err
+		}
+	} else if err := removeDirIfEmpty(".husky"); err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(".husky", 0o755); err != nil {
+	err := updatePackageJSON(func(pkg *pkgjson.File) error {
+		if command, ok := pkg.Script("prepare"); ok && (command == "husky" || command == "husky install") {
+			return pkg.RemoveScript("prepare")
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(".husky", "pre-commit"), []byte(templates.HuskyPreCommit(p)), 0o755)
+	if _, err := fsys.Stat(".git"); err == nil {
+		// git exits non-zero when core.hooksPath was never set, which is fine.
+		_ = runner.RunQuiet("git", "config", "--unset", "core.hooksPath")
+	}
+	return nil
 }
@@ -0,0 +1,128 @@
+package installer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/manifest"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/version"
+)
+
+// WriteManifest records the scaffolded plan, installed package versions and
+// hashes of every generated file in .sparky.json. Besides the features'
+// packages it records everything package.json declares, such as the starter's
+// react and vite and the Vite React plugin, so the manifest used as a stack
+// profile reproduces the whole scaffold.
+func WriteManifest(p plan.Plan) error {
+	m := manifest.New(p)
+	for _, pkg := range declaredDependencies() {
+		if v := installedVersion(pkg.Name); v != "" {
+			m.Packages[pkg.Name] = v
+		}
+	}
+	for _, f := range features {
+		if !f.Enabled(p) {
+			continue
+		}
+		m.SetFeature(f.Name, true)
+		recordPackages(m, f, p)
+	}
+
+	return saveManifest(m)
+}
+
+// updateManifest records that f was added or removed, creating the manifest for
+// projects scaffolded before it existed.
+func updateManifest(f *Feature, p plan.Plan, installed bool) error {
+	m, err := manifest.Load()
+	if os.IsNotExist(err) {
+		m = detectManifest(p)
+	} else if err != nil {
+		return err
+	}
+
+	m.SetFeature(f.Name, installed)
+	if f.Selected != nil {
+		*f.Selected(&m.Plan) = installed
+	}
+
+	if installed {
+		recordPackages(m, f, m.Plan)
+	} else {
+		for _, pkg := range f.Packages {
+			delete(m.Packages, pkg.Name)
+		}
+		for _, path := range f.Files {
+			m.ForgetFile(path)
+		}
+	}
+
+	return saveManifest(m)
+}
+
+// detectManifest rebuilds a manifest from the project on disk.
+func detectManifest(p plan.Plan) *manifest.Manifest {
+	m := manifest.New(plan.Plan{Bundler: p.Bundler})
+	for _, f := range features {
+		if f.Detect == nil || !f.Detect() {
+			continue
+		}
+		m.SetFeature(f.Name, true)
+		if f.Selected != nil {
+			*f.Selected(&m.Plan) = true
+		}
+	}
+	return m
+}
+
+// saveManifest records the files written or deleted by this command and saves the manifest.
+func saveManifest(m *manifest.Manifest) error {
+	for path := range changes.written {
+		if err := m.RecordFile(path); err != nil {
+			return err
+		}
+	}
+	for path := range changes.removed {
+		m.ForgetFile(path)
+	}
+
+	m.Version = version.Sparky
+	return m.Save()
+}
+
+func recordPackages(m *manifest.Manifest, f *Feature, p plan.Plan) {
+	for _, pkg := range f.PackagesFor(p) {
+		if v := installedVersion(pkg.Name); v != "" {
+			m.Packages[pkg.Name] = v
+		}
+	}
+}
+
+// installedVersion returns the version found in node_modules, falling back to
+// the range declared in package.json.
+func installedVersion(name string) string {
+	var pkg struct {
+		Version         string            `json:"version"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+
+	if data, err := fsys.ReadFile(filepath.Join("node_modules", name, "package.json")); err == nil {
+		if json.Unmarshal(data, &pkg) == nil && pkg.Version != "" {
+			return pkg.Version
+		}
+	}
+
+	data, err := fsys.ReadFile("package.json")
+	if err != nil || json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+
+	if v, ok := pkg.Dependencies[name]; ok {
+		return v
+	}
+	return pkg.DevDependencies[name]
+}
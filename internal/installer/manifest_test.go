@@ -0,0 +1,120 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/manifest"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/stack"
+)
+
+func TestWriteManifest_ReproducesStarterVersions(t *testing.T) {
+	scaffolded := t.TempDir()
+	t.Chdir(scaffolded)
+	pkg := `{
+  "name": "app",
+  "dependencies": {
+    "react": "^19.0.0"
+  },
+  "devDependencies": {
+    "@vitejs/plugin-react": "^4.3.4",
+    "vite": "^7.0.0"
+  }
+}
+`
+	if err := os.WriteFile("package.json", []byte(pkg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join("node_modules", "react"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("node_modules", "react", "package.json"), []byte(`{"version": "19.1.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM}
+	if err := WriteManifest(p); err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+	m, err := manifest.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"react": "19.1.0", "vite": "^7.0.0", "@vitejs/plugin-react": "^4.3.4"}
+	for name, version := range want {
+		if got := m.Packages[name]; got != version {
+			t.Errorf("manifest packages[%s] = %q, want %q", name, got, version)
+		}
+	}
+
+	// A newer starter scaffolded with the manifest as its stack gets the recorded versions.
+	t.Chdir(t.TempDir())
+	newer := `{
+  "name": "app",
+  "dependencies": {
+    "react": "^19.2.0"
+  },
+  "devDependencies": {
+    "vite": "^7.1.0"
+  }
+}
+`
+	if err := os.WriteFile("package.json", []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p.Stack = filepath.Join(scaffolded, manifest.Filename)
+	if err := mergeDependencies(p); err != nil {
+		t.Fatalf("mergeDependencies() error = %v", err)
+	}
+	got, err := pkgjson.Load("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, version := range want {
+		if declared, _ := got.Dependency(name); declared.Range != version {
+			t.Errorf("package.json %s = %q, want %q", name, declared.Range, version)
+		}
+	}
+}
+
+func TestMergeDependencies_PinnedStackPinsStarter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	starter := `{
+  "name": "app",
+  "dependencies": {
+    "react": "^19.9.0",
+    "left-pad": "^1.3.0"
+  },
+  "devDependencies": {
+    "vite": "^9.0.0"
+  }
+}
+`
+	if err := os.WriteFile("package.json", []byte(starter), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM, Stack: stack.Pinned}
+	if err := mergeDependencies(p); err != nil {
+		t.Fatalf("mergeDependencies() error = %v", err)
+	}
+
+	prof, err := stack.Load(stack.Pinned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := pkgjson.Load("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"react": prof.Packages["react"], "vite": prof.Packages["vite"], "left-pad": "^1.3.0"}
+	for name, version := range want {
+		if declared, _ := got.Dependency(name); declared.Range != version {
+			t.Errorf("package.json %s = %q, want %q", name, declared.Range, version)
+		}
+	}
+}
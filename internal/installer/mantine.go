@@ -1,94 +1,66 @@
 package installer
 
 import (
-	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/mainfile"
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
-// InstallMantine installs Mantine dependencies.
-func InstallMantine(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Mantine packages")
-	if err := addDependencies(p, false,
-		"@mantine/core@latest",
-		"@mantine/hooks@latest",
-		"@mantine/form@latest",
-		"@mantine/dates@latest",
-		"dayjs@latest",
-		"@mantine/charts@latest",
-		"recharts@latest",
-		"@mantine/notifications@latest",
-		"@mantine/code-highlight@latest",
-		"@mantine/tiptap@latest",
-		"@tiptap/pm@latest",
-		"@tiptap/react@latest",
-		"@tiptap/extension-link@latest",
-		"@tiptap/starter-kit@latest",
-		"@mantine/dropzone@latest",
-		"@mantine/carousel@latest",
-		"embla-carousel@^8.5.2",
-		"embla-carousel-react@^8.5.2",
-		"@mantine/spotlight@latest",
-		"@mantine/modals@latest",
-		"@mantine/nprogress@latest",
-	); err != nil {
-		spin("Failed to install Mantine")
-		return err
-	}
-	spin("Installed Mantine packages")
-
-	spin = logger.StartSpinner("Installing Mantine PostCSS plugins")
-	if err := addDependencies(p, true,
-		"postcss@latest",
-		"postcss-preset-mantine@latest",
-		"postcss-simple-vars@latest",
-	); err != nil {
-		spin("Failed to install Mantine PostCSS plugins")
-		return err
-	}
-	spin("Installed Mantine PostCSS plugins")
-	return nil
+var mantineFeature = &Feature{
+	Name:        "mantine",
+	Title:       "Mantine",
+	FlagUsage:   "Install Mantine",
+	AddUsage:    "Install Mantine and wire it into main.tsx without touching App.tsx",
+	RemoveUsage: "Uninstall Mantine, delete PostCSS config, and unwrap MantineProvider in main.tsx",
+	Packages: []Package{
+		{Name: "@mantine/core"},
+		{Name: "@mantine/hooks"},
+		{Name: "@mantine/form"},
+		{Name: "@mantine/dates"},
+		{Name: "dayjs"},
+		{Name: "@mantine/charts"},
+		{Name: "recharts"},
+		{Name: "@mantine/notifications"},
+		{Name: "@mantine/code-highlight"},
+		{Name: "@mantine/tiptap"},
+		{Name: "@tiptap/pm"},
+		{Name: "@tiptap/react"},
+		{Name: "@tiptap/extension-link"},
+		{Name: "@tiptap/starter-kit"},
+		{Name: "@mantine/dropzone"},
+		{Name: "@mantine/carousel"},
+		{Name: "embla-carousel", Version: "^8.5.2"},
+		{Name: "embla-carousel-react", Version: "^8.5.2"},
+		{Name: "@mantine/spotlight"},
+		{Name: "@mantine/modals"},
+		{Name: "@mantine/nprogress"},
+		{Name: "postcss", Dev: true},
+		{Name: "postcss-preset-mantine", Dev: true},
+		{Name: "postcss-simple-vars", Dev: true},
+	},
+	Files:    []string{"postcss.config.cjs"},
+	Provider: mantineProvider,
+	Selected: func(p *plan.Plan) *bool { return &p.Mantine },
+	Detect:   HasMantineDependency,
+	write: func(plan.Plan) error {
+		return WritePostCSSConfig()
+	},
+	add: func(plan.Plan) error {
+		return WritePostCSSConfig()
+	},
+	remove: func(plan.Plan) error {
+		return DeletePostCSSConfigIfOwned()
+	},
+	verify: func(plan.Plan) []string {
+		if existingFile("postcss.config.cjs", "postcss.config.js", "postcss.config.mjs") == "" {
+			return []string{"Mantine is installed but postcss.config.cjs is missing, so Mantine styles will not compile"}
+		}
+		return nil
+	},
 }
 
-// RemoveMantine removes Mantine dependencies and related PostCSS plugins.
-func RemoveMantine(p plan.Plan) error {
-	spin := logger.StartSpinner("Removing Mantine packages")
-	if err := removeDependencies(p, false,
-		"@mantine/core",
-		"@mantine/hooks",
-		"@mantine/form",
-		"@mantine/dates",
-		"dayjs",
-		"@mantine/charts",
-		"recharts",
-		"@mantine/notifications",
-		"@mantine/code-highlight",
-		"@mantine/tiptap",
-		"@tiptap/pm",
-		"@tiptap/react",
-		"@tiptap/extension-link",
-		"@tiptap/starter-kit",
-		"@mantine/dropzone",
-		"@mantine/carousel",
-		"embla-carousel",
-		"embla-carousel-react",
-		"@mantine/spotlight",
-		"@mantine/modals",
-		"@mantine/nprogress",
-	); err != nil {
-		spin("Failed to remove Mantine")
-		return err
-	}
-	spin("Removed Mantine packages")
-
-	spin = logger.StartSpinner("Removing Mantine PostCSS plugins")
-	if err := removeDependencies(p, true,
-		"postcss",
-		"postcss-preset-mantine",
-		"postcss-simple-vars",
-	); err != nil {
-		spin("Failed to remove Mantine PostCSS plugins")
-		return err
-	}
-	spin("Removed Mantine PostCSS plugins")
-	return nil
+// mantineProvider sits inside QueryClientProvider so Mantine components can use queries.
+var mantineProvider = &mainfile.Provider{
+	Element: "MantineProvider",
+	Imports: []mainfile.Import{{Names: []string{"MantineProvider"}, Module: "@mantine/core"}},
+	Inside:  []*mainfile.Provider{queryProvider},
 }
@@ -0,0 +1,222 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/mainfile"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+var mswFeature = &Feature{
+	Name:        "msw",
+	Title:       "MSW",
+	FlagUsage:   "Add Mock Service Worker with a sample endpoint, started in development (Vite only)",
+	AddUsage:    "Install MSW, generate its service worker and start it from main.tsx in development (App.tsx untouched)",
+	RemoveUsage: "Uninstall MSW, stop starting it in main.tsx and delete the generated mocks if unmodified",
+	Bundler:     plan.BundlerVite,
+	Packages: []Package{
+		{Name: "msw", Dev: true},
+	},
+	Files: []string{
+		"src/mocks/handlers.ts",
+		"src/mocks/browser.ts",
+		"src/hooks/useSparky.ts",
+		mswWorkerPath,
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.MSW },
+	Detect:   HasMSW,
+	check:    checkMSW,
+	write: func(p plan.Plan) error {
+		if err := writeMSWFiles(p.ReactQuery); err != nil {
+			return err
+		}
+		if p.NoInstall {
+			logger.Warning("\nSkipping the MSW service worker because dependencies are not installed. Run `" + mswInitCommand(p) + "` after installing.")
+		}
+		return nil
+	},
+	installed: initMockServiceWorker,
+	add:       addMSW,
+	remove:    removeMSW,
+	verify:    verifyMSW,
+}
+
+// mswWorkerPath is the service worker `msw init` copies into the public directory.
+const mswWorkerPath = "public/mockServiceWorker.js"
+
+// mswFiles are the handlers, the worker setup and the sample query hook.
+var mswFiles = []struct {
+	path, content string
+	query         bool // only written when TanStack Query is installed
+}{
+	{filepath.Join("src", "mocks", "handlers.ts"), templates.MSWHandlers, false},
+	{filepath.Join("src", "mocks", "browser.ts"), templates.MSWBrowser, false},
+	{filepath.Join("src", "hooks", "useSparky.ts"), templates.SparkyQuery, true},
+}
+
+// HasMSW reports whether package.json declares msw.
+func HasMSW() bool {
+	return hasDependency("msw")
+}
+
+// checkMSW refuses projects whose src/mocks already sets up a worker go-sparky did not write.
+func checkMSW(plan.Plan) (bool, error) {
+	path := existingFile(filepath.Join("src", "mocks", "browser.ts"), filepath.Join("src", "mocks", "browser.js"))
+	if path == "" {
+		return true, nil
+	}
+	if data, err := fsys.ReadFile(path); err == nil && string(data) == templates.MSWBrowser {
+		return true, nil
+	}
+	return false, fmt.Errorf("%s already sets up mocking; remove it first to use the go-sparky mocks", filepath.ToSlash(path))
+}
+
+// writeMSWFiles writes the handlers and worker setup unless they exist and,
+// with TanStack Query, a sample query hook that reads the mocked endpoint.
+func writeMSWFiles(query bool) error {
+	for _, f := range mswFiles {
+		if f.query && !query || fileExists(f.path) {
+			continue
+		}
+		if err := fsys.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return err
+		}
+		if err := writeFile(f.path, []byte(f.content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mswInitCommand is the shell command that copies the service worker into public/.
+func mswInitCommand(p plan.Plan) string {
+	return p.PackageManager().Exec("msw init public --save")
+}
+
+// initMockServiceWorker runs `msw init`, which writes the worker script and
+// records its directory in package.json so upgrades refresh it.
+func initMockServiceWorker(p plan.Plan) error {
+	spin := logger.StartSpinner("Generating the MSW service worker")
+	bin, args := p.PackageManager().ExecArgs("msw", "init", "public", "--save")
+	if err := runner.RunQuiet(bin, args...); err != nil {
+		spin("Failed to generate the MSW service worker")
+		return err
+	}
+	spin("Generated " + mswWorkerPath)
+
+	// msw init writes the worker itself; tracking it lets `remove msw` record the deletion.
+	trackFile(filepath.FromSlash(mswWorkerPath))
+	return nil
+}
+
+func addMSW(p plan.Plan) error {
+	if err := writeMSWFiles(HasReactQueryDependency()); err != nil {
+		return err
+	}
+	if err := initMockServiceWorker(p); err != nil {
+		return err
+	}
+	if err := startWorkerInMain(p); err != nil {
+		return err
+	}
+
+	msg := "\nMSW added. Edit src/mocks/handlers.ts to mock your API; the worker only starts in development."
+	if HasReactQueryDependency() {
+		msg += " The useSparky hook in src/hooks queries the sample endpoint; App.tsx left untouched."
+	}
+	logger.Info(msg)
+	return nil
+}
+
+// startWorkerInMain inserts templates.MockingStart into the entry file before
+// the top-level statement that sets up the root, so the worker is ready before
+// the first render. A root set up inside a function gets manual steps instead.
+func startWorkerInMain(p plan.Plan) error {
+	data, err := readMainEntry(p)
+	if err != nil {
+		return err
+	}
+	mainPath := filepath.Join("src", MainEntryFilename(p))
+	if strings.Contains(string(data), "./mocks/browser") {
+		return nil
+	}
+
+	updated, err := mainfile.InsertBeforeRoot(data, templates.MockingStart+"\n")
+	if errors.Is(err, mainfile.ErrUnsupported) {
+		logger.Warning("\nCould not update " + filepath.ToSlash(mainPath) + " automatically (" + err.Error() + "). Start the worker before rendering:\n\n" + templates.MockingStart)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return writeFile(mainPath, updated, 0o644)
+}
+
+// stopWorkerInMain deletes the block startWorkerInMain inserted.
+func stopWorkerInMain(p plan.Plan) error {
+	mainPath := filepath.Join("src", MainEntryFilename(p))
+	data, err := fsys.ReadFile(mainPath)
+	if err != nil {
+		return nil
+	}
+
+	content := string(data)
+	for _, block := range []string{templates.MockingStart + "\n", templates.MockingStart} {
+		if strings.Contains(content, block) {
+			return writeFile(mainPath, []byte(strings.Replace(content, block, "", 1)), 0o644)
+		}
+	}
+	if strings.Contains(content, "./mocks/browser") {
+		logger.Warning("\n" + filepath.ToSlash(mainPath) + " still imports ./mocks/browser; remove the code that starts the worker.")
+	}
+	return nil
+}
+
+func removeMSW(p plan.Plan) error {
+	if err := stopWorkerInMain(p); err != nil {
+		return err
+	}
+
+	for _, f := range mswFiles {
+		if err := deleteFileIfContentMatches(f.path, f.content); err != nil {
+			return err
+		}
+		if err := removeDirIfEmpty(filepath.Dir(f.path)); err != nil {
+			return err
+		}
+	}
+
+	// The worker script belongs to the uninstalled msw version, so it goes either way.
+	if fileExists(filepath.FromSlash(mswWorkerPath)) {
+		if err := removeFile(filepath.FromSlash(mswWorkerPath)); err != nil {
+			return err
+		}
+	}
+	if !hasPackageJSONField("msw") {
+		return nil
+	}
+	return updatePackageJSON(func(pkg *pkgjson.File) error {
+		pkg.RemoveField("msw")
+		return nil
+	})
+}
+
+// verifyMSW reports an MSW install whose worker is missing or never started.
+func verifyMSW(p plan.Plan) []string {
+	var issues []string
+	if !fileExists(filepath.FromSlash(mswWorkerPath)) {
+		issues = append(issues, "MSW is installed but "+mswWorkerPath+" is missing; run `"+mswInitCommand(p)+"`")
+	}
+	if data, err := fsys.ReadFile(filepath.Join("src", MainEntryFilename(p))); err == nil && !strings.Contains(string(data), "./mocks/browser") {
+		issues = append(issues, "MSW is installed but src/main.tsx never starts the worker, so requests are not mocked")
+	}
+	return issues
+}
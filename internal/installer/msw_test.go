@@ -0,0 +1,174 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+// commandLog records commands instead of running them.
+type commandLog []runner.Command
+
+func (l *commandLog) Execute(c runner.Command) error {
+	*l = append(*l, c)
+	return nil
+}
+
+func TestMSW_AddRemoveLeavesAppAlone(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var log commandLog
+	defer runner.Use(&log)()
+
+	p := plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM, Mantine: true, ReactQuery: true}
+	pkg := `{
+  "name": "app",
+  "dependencies": {
+    "@tanstack/react-query": "^5.62.0"
+  },
+  "msw": {
+    "workerDirectory": ["public"]
+  }
+}
+`
+	files := map[string]string{
+		"package.json":                   pkg,
+		filepath.Join("src", "main.tsx"): templates.MainTemplate(p),
+		filepath.Join("src", "App.tsx"):  templates.AppTemplate(p),
+	}
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := addMSW(p); err != nil {
+		t.Fatalf("addMSW() error = %v", err)
+	}
+
+	want := []string{"msw", "init", "public", "--save"}
+	if len(log) != 1 || log[0].Name != "pnpm" || !reflect.DeepEqual(log[0].Args, want) {
+		t.Errorf("addMSW() ran %v, want pnpm %v", log, want)
+	}
+	withMSW := p
+	withMSW.MSW = true
+	if got, _ := os.ReadFile(filepath.Join("src", "main.tsx")); string(got) != templates.MainTemplate(withMSW) {
+		t.Errorf("main.tsx =\n%s\nwant\n%s", got, templates.MainTemplate(withMSW))
+	}
+	for _, f := range mswFiles {
+		if got, _ := os.ReadFile(f.path); string(got) != f.content {
+			t.Errorf("%s =\n%s", f.path, got)
+		}
+	}
+
+	// Stand in for the worker msw init would have written.
+	if err := os.Mkdir("public", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.FromSlash(mswWorkerPath), []byte("/* worker */\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeMSW(p); err != nil {
+		t.Fatalf("removeMSW() error = %v", err)
+	}
+
+	for path, content := range files {
+		if path == "package.json" {
+			continue
+		}
+		if got, _ := os.ReadFile(path); string(got) != content {
+			t.Errorf("%s after remove =\n%s\nwant\n%s", path, got, content)
+		}
+	}
+	if got, _ := os.ReadFile("package.json"); hasPackageJSONField("msw") {
+		t.Errorf("package.json still configures msw:\n%s", got)
+	}
+	if fileExists(filepath.Join("src", "mocks")) || fileExists(filepath.Join("src", "hooks")) || fileExists(filepath.FromSlash(mswWorkerPath)) {
+		t.Error("generated mocks were kept")
+	}
+}
+
+func TestStartWorkerInMain_CustomEntry(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	main := `import { StrictMode } from 'react';
+import { createRoot } from 'react-dom/client';
+
+import App from './App';
+
+createRoot(document.getElementById('root')!).render(
+  <StrictMode>
+    <App />
+  </StrictMode>,
+);
+`
+	path := filepath.Join("src", "main.tsx")
+	if err := os.WriteFile(path, []byte(main), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.Plan{Bundler: plan.BundlerVite}
+	if err := startWorkerInMain(p); err != nil {
+		t.Fatalf("startWorkerInMain() error = %v", err)
+	}
+	want := `import { StrictMode } from 'react';
+import { createRoot } from 'react-dom/client';
+
+import App from './App';
+
+` + templates.MockingStart + `
+createRoot(document.getElementById('root')!).render(
+  <StrictMode>
+    <App />
+  </StrictMode>,
+);
+`
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Errorf("main.tsx =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := stopWorkerInMain(p); err != nil {
+		t.Fatalf("stopWorkerInMain() error = %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != main {
+		t.Errorf("main.tsx after stop =\n%s", got)
+	}
+}
+
+func TestStartWorkerInMain_RootInsideFunction(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	main := `import { createRoot } from 'react-dom/client';
+
+import App from './App';
+
+function mount() {
+  createRoot(document.getElementById('root')!).render(<App />);
+}
+
+document.addEventListener('DOMContentLoaded', mount);
+`
+	path := filepath.Join("src", "main.tsx")
+	if err := os.WriteFile(path, []byte(main), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := startWorkerInMain(plan.Plan{Bundler: plan.BundlerVite}); err != nil {
+		t.Fatalf("startWorkerInMain() error = %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != main {
+		t.Errorf("main.tsx was rewritten:\n%s", got)
+	}
+}
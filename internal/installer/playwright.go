@@ -0,0 +1,218 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/registry"
+	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+var playwrightFeature = &Feature{
+	Name:        "playwright",
+	Title:       "Playwright",
+	FlagUsage:   "Add Playwright end-to-end tests with a smoke test run against the dev server",
+	AddUsage:    "Install Playwright, write playwright.config.ts for the dev server and an e2e smoke test",
+	RemoveUsage: "Uninstall Playwright and delete its config and smoke test if unmodified",
+	Packages: []Package{
+		{Name: "@playwright/test", Dev: true},
+	},
+	Scripts: []Script{
+		{Name: "test:e2e", Command: "playwright test"},
+		{Name: "test:e2e:ui", Command: "playwright test --ui"},
+	},
+	Files:    []string{"playwright.config.ts", "e2e/smoke.e2e.ts"},
+	Selected: func(p *plan.Plan) *bool { return &p.Playwright },
+	Detect:   HasPlaywright,
+	check:    checkPlaywright,
+	write: func(p plan.Plan) error {
+		if err := writePlaywrightFiles(p); err != nil {
+			return err
+		}
+		return addIgnoreEntries(playwrightIgnoreFiles, playwrightOutputs)
+	},
+	installed: func(p plan.Plan) error {
+		if p.Browsers {
+			InstallPlaywrightBrowsers(p)
+		}
+		return nil
+	},
+	add:    addPlaywright,
+	remove: removePlaywright,
+	verify: verifyPlaywright,
+}
+
+// playwrightOutputs are the folders Playwright writes reports and traces to.
+var playwrightOutputs = []string{"playwright-report", "test-results"}
+
+// playwrightIgnoreFiles are the line-based ignore files that should skip playwrightOutputs.
+var playwrightIgnoreFiles = []string{".prettierignore", ".gitignore"}
+
+// playwrightConfigs are the config files Playwright loads.
+var playwrightConfigs = []string{
+	"playwright.config.ts", "playwright.config.js", "playwright.config.mts", "playwright.config.mjs", "playwright.config.cjs",
+}
+
+// HasPlaywright reports whether package.json declares @playwright/test.
+func HasPlaywright() bool {
+	return hasDependency("@playwright/test")
+}
+
+// checkPlaywright refuses projects that already configure Playwright some other way.
+func checkPlaywright(plan.Plan) (bool, error) {
+	path := existingFile(playwrightConfigs...)
+	if path == "" {
+		return true, nil
+	}
+	if data, err := fsys.ReadFile(path); err == nil && slices.Contains(generatedVariants(templates.PlaywrightConfig), string(data)) {
+		logger.Warning("\n" + path + " already uses the go-sparky config; leaving it unchanged.")
+		return false, nil
+	}
+	return false, fmt.Errorf("%s already configures Playwright; remove it first to use the go-sparky config", path)
+}
+
+// writePlaywrightFiles writes playwright.config.ts and, unless one exists, the smoke test.
+func writePlaywrightFiles(p plan.Plan) error {
+	if err := writeFile("playwright.config.ts", []byte(templates.PlaywrightConfig(p)), 0o644); err != nil {
+		return err
+	}
+	if err := fsys.MkdirAll("e2e", 0o755); err != nil {
+		return err
+	}
+
+	testPath := filepath.Join("e2e", "smoke.e2e.ts")
+	if fileExists(testPath) {
+		return nil
+	}
+	return writeFile(testPath, []byte(templates.PlaywrightSmokeTest), 0o644)
+}
+
+func addPlaywright(p plan.Plan) error {
+	if err := writePlaywrightFiles(p); err != nil {
+		return err
+	}
+	if err := addIgnoreEntries(playwrightIgnoreFiles, playwrightOutputs); err != nil {
+		return err
+	}
+
+	ok, err := rewriteESLintConfig(func(p *plan.Plan) { p.Playwright = true })
+	if err != nil {
+		return err
+	}
+	if data, err := fsys.ReadFile("eslint.config.js"); err == nil && !ok && !strings.Contains(string(data), "playwright-report") {
+		logger.Info("\neslint.config.js is customized; add \"playwright-report\" and \"test-results\" to its ignores.")
+	}
+
+	m := p.PackageManager()
+	if p.Browsers {
+		InstallPlaywrightBrowsers(p)
+		logger.Info("\nPlaywright added. Run `" + m.Run("test:e2e") + "`.")
+		return nil
+	}
+	logger.Info("\nPlaywright added. Download a browser with `" + m.Exec("playwright install chromium") + "`, then run `" + m.Run("test:e2e") + "`.")
+	return nil
+}
+
+// InstallPlaywrightBrowsers downloads the Chromium build the generated config
+// tests with. A failed download only warns: the tests can fetch it later.
+func InstallPlaywrightBrowsers(p plan.Plan) {
+	m := p.PackageManager()
+	manual := m.Exec("playwright install chromium")
+	if registry.Offline() {
+		logger.Warning("\nOffline; skipping the Playwright browser download. Run `" + manual + "` once you are online.")
+		return
+	}
+
+	spin := logger.StartSpinner("Downloading Chromium for Playwright")
+	bin, args := m.ExecArgs("playwright", "install", "chromium")
+	if err := runner.RunQuiet(bin, args...); err != nil {
+		spin("Failed to download Chromium")
+		logger.Warning("\nCould not download the Playwright browser: " + err.Error() + "\nRun `" + manual + "` to retry.")
+		return
+	}
+	spin("Downloaded Chromium for Playwright")
+}
+
+func removePlaywright(plan.Plan) error {
+	if err := deleteFileIfContentMatches("playwright.config.ts", generatedVariants(templates.PlaywrightConfig)...); err != nil {
+		return err
+	}
+	if err := deleteFileIfContentMatches(filepath.Join("e2e", "smoke.e2e.ts"), templates.PlaywrightSmokeTest); err != nil {
+		return err
+	}
+	if err := removeDirIfEmpty("e2e"); err != nil {
+		return err
+	}
+	if err := removeIgnoreEntries(playwrightIgnoreFiles, playwrightOutputs); err != nil {
+		return err
+	}
+
+	_, err := rewriteESLintConfig(func(p *plan.Plan) { p.Playwright = false })
+	return err
+}
+
+// verifyPlaywright reports a Playwright install without a config to start the dev server.
+func verifyPlaywright(plan.Plan) []string {
+	if existingFile(playwrightConfigs...) == "" {
+		return []string{"Playwright is installed but playwright.config.ts is missing, so `test:e2e` does not start the dev server"}
+	}
+	return nil
+}
+
+// addIgnoreEntries appends entries missing from each existing line-based ignore file.
+func addIgnoreEntries(paths, entries []string) error {
+	return editIgnoreFiles(paths, func(lines []string) []string {
+		for _, entry := range entries {
+			if !slices.Contains(lines, entry) && !slices.Contains(lines, entry+"/") {
+				lines = append(lines, entry)
+			}
+		}
+		return lines
+	})
+}
+
+// removeIgnoreEntries drops entries from each existing line-based ignore file.
+func removeIgnoreEntries(paths, entries []string) error {
+	return editIgnoreFiles(paths, func(lines []string) []string {
+		return slices.DeleteFunc(lines, func(line string) bool {
+			return slices.Contains(entries, line)
+		})
+	})
+}
+
+// editIgnoreFiles rewrites the lines of each ignore file that exists, leaving
+// files edit does not change untouched.
+func editIgnoreFiles(paths []string, edit func(lines []string) []string) error {
+	for _, path := range paths {
+		data, err := fsys.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if len(data) == 0 {
+			lines = nil
+		}
+		var content string
+		if lines = edit(lines); len(lines) > 0 {
+			content = strings.Join(lines, "\n") + "\n"
+		}
+		if content == string(data) {
+			continue
+		}
+		if err := writeFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+func TestPlaywright_AddRemoveRoundTrips(t *testing.T) {
+	t.Chdir(t.TempDir())
+	p := plan.Plan{Bundler: plan.BundlerBun, PM: pm.Bun}
+	gitignore := "node_modules\ntest-results/\n"
+	files := map[string]string{
+		"eslint.config.js": templates.EslintConfig(p),
+		".prettierignore":  templates.PrettierIgnore(),
+		".gitignore":       gitignore,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := addPlaywright(p); err != nil {
+		t.Fatalf("addPlaywright() error = %v", err)
+	}
+
+	withPlaywright := p
+	withPlaywright.Playwright = true
+	want := map[string]string{
+		"playwright.config.ts":               templates.PlaywrightConfig(p),
+		filepath.Join("e2e", "smoke.e2e.ts"): templates.PlaywrightSmokeTest,
+		"eslint.config.js":                   templates.EslintConfig(withPlaywright),
+		".prettierignore":                    templates.PrettierIgnore() + "playwright-report\ntest-results\n",
+		".gitignore":                         gitignore + "playwright-report\n",
+	}
+	for path, content := range want {
+		if got, _ := os.ReadFile(path); string(got) != content {
+			t.Errorf("%s =\n%s\nwant\n%s", path, got, content)
+		}
+	}
+
+	if err := removePlaywright(p); err != nil {
+		t.Fatalf("removePlaywright() error = %v", err)
+	}
+	for path, content := range files {
+		if got, _ := os.ReadFile(path); string(got) != content {
+			t.Errorf("%s after remove =\n%s\nwant\n%s", path, got, content)
+		}
+	}
+	if fileExists("playwright.config.ts") || fileExists("e2e") {
+		t.Error("generated Playwright files were kept")
+	}
+}
+
+func TestPlaywrightConfig_StartsBundlerDevServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       plan.Plan
+		command string
+		url     string
+	}{
+		{"vite", plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM}, "command: 'pnpm dev'", "http://localhost:5173"},
+		{"vite npm", plan.Plan{Bundler: plan.BundlerVite, PM: pm.NPM}, "command: 'npm run dev'", "http://localhost:5173"},
+		{"bun", plan.Plan{Bundler: plan.BundlerBun}, "command: 'bun dev'", "http://localhost:3000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := templates.PlaywrightConfig(tt.p)
+			if !strings.Contains(cfg, tt.command) || !strings.Contains(cfg, "url: '"+tt.url+"'") {
+				t.Errorf("PlaywrightConfig() does not start %q on %s:\n%s", tt.command, tt.url, cfg)
+			}
+		})
+	}
+}
+
+func TestCheckPlaywright_RefusesExistingConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("playwright.config.js", []byte("export default {};\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := checkPlaywright(plan.Plan{Bundler: plan.BundlerVite}); err == nil || !strings.Contains(err.Error(), "playwright.config.js") {
+		t.Errorf("checkPlaywright() error = %v, want a refusal naming playwright.config.js", err)
+	}
+}
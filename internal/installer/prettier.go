@@ -1,29 +1,95 @@
 package installer
 
 import (
-	"os"
+	"fmt"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 	"github.com/hotslug/go-sparky/internal/templates"
 )
 
-// InstallPrettier installs Prettier and writes the config file.
-func InstallPrettier(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Prettier")
-	if err := addDependencies(p, true,
-		"prettier@latest",
-		"prettier-plugin-tailwindcss@latest",
-		"@ianvs/prettier-plugin-sort-imports@latest",
-	); err != nil {
-		spin("Failed to install Prettier")
-		return err
+var prettierFeature = &Feature{
+	Name:        "prettier",
+	Title:       "Prettier",
+	Default:     true,
+	FlagUsage:   "Skip Prettier (default installs)",
+	AddUsage:    "Install Prettier with the go-sparky config and format scripts",
+	RemoveUsage: "Uninstall Prettier and delete .prettierrc and .prettierignore if unmodified",
+	Packages: []Package{
+		{Name: "prettier", Dev: true},
+		{Name: "prettier-plugin-tailwindcss", Dev: true},
+		{Name: "@ianvs/prettier-plugin-sort-imports", Dev: true},
+	},
+	Scripts: []Script{
+		{Name: "format", Command: "prettier --write ."},
+		{Name: "format:check", Command: "prettier --check ."},
+	},
+	Files:    []string{".prettierrc", ".prettierignore"},
+	Selected: func(p *plan.Plan) *bool { return &p.Prettier },
+	Detect:   func() bool { return fileExists(".prettierrc") },
+	setup: func(plan.Plan) error {
+		return WritePrettierConfig()
+	},
+	check: checkPrettier,
+	add: func(p plan.Plan) error {
+		if err := writeFile(".prettierrc", []byte(templates.PrettierConfig()), 0o644); err != nil {
+			return err
+		}
+		// An existing ignore list is the user's; only write ours when there is none.
+		if !fileExists(".prettierignore") {
+			if err := writeFile(".prettierignore", []byte(templates.PrettierIgnore()), 0o644); err != nil {
+				return err
+			}
+		}
+		logger.Info("\nPrettier added. Run `" + p.PackageManager().Run("format") + "` to format the project.")
+		return nil
+	},
+	remove: func(plan.Plan) error {
+		return DeletePrettierConfigIfOwned()
+	},
+	verify: func(plan.Plan) []string {
+		return missingPackages("Prettier", "prettier")
+	},
+}
+
+// checkPrettier refuses projects that already configure Prettier some other way.
+func checkPrettier(plan.Plan) (bool, error) {
+	if data, err := fsys.ReadFile(".prettierrc"); err == nil {
+		if string(data) == templates.PrettierConfig() {
+			logger.Warning("\n.prettierrc already uses the go-sparky config; leaving it unchanged.")
+			return false, nil
+		}
+		return false, fmt.Errorf(".prettierrc already configures Prettier; remove it first to use the go-sparky config")
 	}
-	spin("Installed Prettier")
 
-	if err := os.WriteFile(".prettierrc", []byte(templates.PrettierConfig()), 0o644); err != nil {
+	other := existingFile(
+		".prettierrc.json", ".prettierrc.json5", ".prettierrc.yml", ".prettierrc.yaml", ".prettierrc.toml",
+		".prettierrc.js", ".prettierrc.cjs", ".prettierrc.mjs", ".prettierrc.ts",
+		"prettier.config.js", "prettier.config.cjs", "prettier.config.mjs", "prettier.config.ts",
+	)
+	if other != "" {
+		return false, fmt.Errorf("%s already configures Prettier; remove it first to use the go-sparky config", other)
+	}
+	if hasPackageJSONField("prettier") {
+		return false, fmt.Errorf("package.json already configures Prettier in \"prettier\"; remove it first to use the go-sparky config")
+	}
+	return true, nil
+}
+
+// WritePrettierConfig writes .prettierrc and .prettierignore.
+func WritePrettierConfig() error {
+	if err := writeFile(".prettierrc", []byte(templates.PrettierConfig()), 0o644); err != nil {
 		return err
 	}
 
-	return os.WriteFile(".prettierignore", []byte(templates.PrettierIgnore()), 0o644)
+	return writeFile(".prettierignore", []byte(templates.PrettierIgnore()), 0o644)
+}
+
+// DeletePrettierConfigIfOwned deletes .prettierrc and .prettierignore when they match generated content.
+func DeletePrettierConfigIfOwned() error {
+	if err := deleteFileIfContentMatches(".prettierrc", templates.PrettierConfig()); err != nil {
+		return err
+	}
+	return deleteFileIfContentMatches(".prettierignore", templates.PrettierIgnore())
 }
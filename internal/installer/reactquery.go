@@ -1,17 +1,33 @@
 package installer
 
 import (
-	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/mainfile"
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
-// InstallReactQuery installs TanStack Query dependencies.
-func InstallReactQuery(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing TanStack Query")
-	if err := addDependencies(p, false, "@tanstack/react-query@latest", "@tanstack/react-query-devtools@latest"); err != nil {
-		spin("Failed to install TanStack Query")
-		return err
-	}
-	spin("Installed TanStack Query")
-	return nil
+var reactQueryFeature = &Feature{
+	Name:        "react-query",
+	Title:       "TanStack Query",
+	Default:     true,
+	FlagUsage:   "Skip TanStack Query (default installs)",
+	AddUsage:    "Install TanStack Query and wrap providers in main.tsx (App.tsx untouched)",
+	RemoveUsage: "Uninstall TanStack Query and unwrap providers in main.tsx",
+	Packages: []Package{
+		{Name: "@tanstack/react-query"},
+		{Name: "@tanstack/react-query-devtools"},
+	},
+	Provider: queryProvider,
+	Selected: func(p *plan.Plan) *bool { return &p.ReactQuery },
+	Detect:   HasReactQueryDependency,
+}
+
+var queryProvider = &mainfile.Provider{
+	Element: "QueryClientProvider",
+	Attrs:   "client={queryClient}",
+	Imports: []mainfile.Import{
+		{Names: []string{"QueryClient", "QueryClientProvider"}, Module: "@tanstack/react-query"},
+		{Names: []string{"ReactQueryDevtools"}, Module: "@tanstack/react-query-devtools"},
+	},
+	Setup:  "const queryClient = new QueryClient();",
+	Extras: []string{"<ReactQueryDevtools initialIsOpen={false} />"},
 }
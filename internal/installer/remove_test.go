@@ -0,0 +1,220 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/manifest"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/runner"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+// addHuskyForTest runs addHusky in a git repository, standing in for the
+// helper directory and prepare script husky-init would have added.
+func addHuskyForTest(t *testing.T, p plan.Plan) {
+	t.Helper()
+	var log commandLog
+	t.Cleanup(runner.Use(&log))
+
+	if err := os.Mkdir(".git", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pkg := "{\n  \"name\": \"app\",\n  \"scripts\": {\n    \"prepare\": \"husky install\"\n  }\n}\n"
+	if err := os.WriteFile("package.json", []byte(pkg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := addHusky(p); err != nil {
+		t.Fatalf("addHusky() error = %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(".husky", "_"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".husky", "_", "husky.sh"), []byte("#!/usr/bin/env sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addShadcnForTest stands in for shadcn-ui init and records its files in .sparky.json.
+func addShadcnForTest(t *testing.T, p plan.Plan) {
+	t.Helper()
+	utils := filepath.FromSlash(shadcnUtilsPath)
+	if err := os.MkdirAll(filepath.Dir(utils), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"components.json": "{\n  \"style\": \"new-york\"\n}\n",
+		utils:             "export function cn() {}\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		trackFile(path)
+	}
+	if err := saveManifest(manifest.New(p)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addStorybookForTest writes the Storybook config next to the stylesheet its preview imports.
+func addStorybookForTest(t *testing.T, p plan.Plan) {
+	t.Helper()
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("src", "index.css"), []byte("body {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := addStorybookConfig(p); err != nil {
+		t.Fatalf("addStorybookConfig() error = %v", err)
+	}
+}
+
+func TestRemove_RoundTrips(t *testing.T) {
+	vite := plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM}
+	husky := plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM, Eslint: true, Prettier: true, Husky: true}
+	shadcn := plan.Plan{Bundler: plan.BundlerVite, PM: pm.PNPM, Tailwind: true, Shadcn: true}
+
+	tests := []struct {
+		name      string
+		plan      plan.Plan
+		add       func(t *testing.T, p plan.Plan)
+		remove    func(plan.Plan) error
+		generated map[string]string  // content add writes, checked before removing
+		edit      string             // file changed by hand in the edited case
+		gone      []string           // paths remove deletes unless they hold the edited file
+		kept      []string           // paths remove leaves alone
+		check     func(t *testing.T) // extra checks after removing a pristine install
+	}{
+		{
+			name: "eslint",
+			plan: vite,
+			add: func(t *testing.T, p plan.Plan) {
+				if err := eslintFeature.add(p); err != nil {
+					t.Fatalf("add() error = %v", err)
+				}
+			},
+			remove:    eslintFeature.remove,
+			generated: map[string]string{"eslint.config.js": templates.EslintConfig(vite)},
+			edit:      "eslint.config.js",
+			gone:      []string{"eslint.config.js"},
+		},
+		{
+			name: "prettier",
+			plan: vite,
+			add: func(t *testing.T, p plan.Plan) {
+				if err := prettierFeature.add(p); err != nil {
+					t.Fatalf("add() error = %v", err)
+				}
+			},
+			remove: prettierFeature.remove,
+			generated: map[string]string{
+				".prettierrc":     templates.PrettierConfig(),
+				".prettierignore": templates.PrettierIgnore(),
+			},
+			edit: ".prettierrc",
+			gone: []string{".prettierrc", ".prettierignore"},
+		},
+		{
+			name:   "husky",
+			plan:   husky,
+			add:    addHuskyForTest,
+			remove: removeHusky,
+			generated: map[string]string{
+				".lintstagedrc":                       templates.LintStagedConfig(husky),
+				filepath.Join(".husky", "pre-commit"): templates.HuskyPreCommit(husky),
+			},
+			edit: filepath.Join(".husky", "pre-commit"),
+			gone: []string{".lintstagedrc", ".husky"},
+			check: func(t *testing.T) {
+				pkg, err := pkgjson.Load("package.json")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := pkg.Script("prepare"); ok {
+					t.Error("the husky prepare script was kept")
+				}
+			},
+		},
+		{
+			name:      "storybook",
+			plan:      vite,
+			add:       addStorybookForTest,
+			remove:    removeStorybookFiles,
+			generated: map[string]string{filepath.Join(".storybook", "preview.ts"): storybookPreview(true)},
+			edit:      filepath.Join(".storybook", "preview.ts"),
+			gone:      []string{".storybook", filepath.Join("src", "stories")},
+			kept:      []string{filepath.Join("src", "index.css")},
+		},
+		{
+			name:   "shadcn",
+			plan:   shadcn,
+			add:    addShadcnForTest,
+			remove: removeShadcnFiles,
+			edit:   "components.json",
+			gone:   []string{"components.json", filepath.Dir(filepath.FromSlash(shadcnUtilsPath))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/pristine", func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			tt.add(t, tt.plan)
+			for path, content := range tt.generated {
+				if got, _ := os.ReadFile(path); string(got) != content {
+					t.Fatalf("%s =\n%s\nwant\n%s", path, got, content)
+				}
+			}
+
+			if err := tt.remove(tt.plan); err != nil {
+				t.Fatalf("remove() error = %v", err)
+			}
+			for _, path := range tt.gone {
+				if fileExists(path) {
+					t.Errorf("generated %s was kept", path)
+				}
+			}
+			for _, path := range tt.kept {
+				if !fileExists(path) {
+					t.Errorf("%s was deleted", path)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+
+		t.Run(tt.name+"/edited", func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			tt.add(t, tt.plan)
+			data, err := os.ReadFile(tt.edit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			edited := string(data) + "// edited\n"
+			if err := os.WriteFile(tt.edit, []byte(edited), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.remove(tt.plan); err != nil {
+				t.Fatalf("remove() error = %v", err)
+			}
+			if got, _ := os.ReadFile(tt.edit); string(got) != edited {
+				t.Errorf("edited %s =\n%s\nwant it kept", tt.edit, got)
+			}
+			for _, path := range tt.gone {
+				if path == tt.edit || strings.HasPrefix(tt.edit, path+string(filepath.Separator)) {
+					continue
+				}
+				if fileExists(path) {
+					t.Errorf("unmodified %s was kept", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,499 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/mainfile"
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+// Router kinds accepted by --router and `add router --kind`.
+const (
+	RouterReactRouter = "react-router"
+	RouterTanStack    = "tanstack"
+)
+
+// RouterKinds lists the router kinds in the order they are offered.
+var RouterKinds = []string{RouterReactRouter, RouterTanStack}
+
+// RouterFeature returns the feature that installs the router kind.
+func RouterFeature(kind string) (*Feature, error) {
+	switch kind {
+	case RouterReactRouter:
+		return reactRouterFeature, nil
+	case RouterTanStack:
+		return tanstackRouterFeature, nil
+	}
+	return nil, fmt.Errorf("unknown router %q; use one of: %s", kind, strings.Join(RouterKinds, ", "))
+}
+
+// InstalledRouter returns the router feature the current project uses, or nil.
+func InstalledRouter() *Feature {
+	for _, f := range []*Feature{reactRouterFeature, tanstackRouterFeature} {
+		if f.Detect() {
+			return f
+		}
+	}
+	return nil
+}
+
+// appImport is the import a router takes over from main.tsx and gives back on removal.
+var appImport = mainfile.Import{Default: "App", Module: "./App"}
+
+var reactRouterFeature = &Feature{
+	Name:      "react-router",
+	Title:     "React Router",
+	Packages:  []Package{{Name: "react-router"}},
+	Conflicts: []string{"tanstack-router"},
+	Files: []string{
+		"src/router.ts",
+		"src/routes/root.tsx",
+		"src/routes/index.tsx",
+		"src/routes/not-found.tsx",
+	},
+	Provider: &mainfile.Provider{
+		Element: "RouterProvider",
+		Attrs:   "router={router}",
+		Imports: []mainfile.Import{
+			{Names: []string{"RouterProvider"}, Module: "react-router"},
+			{Names: []string{"router"}, Module: "./router"},
+		},
+		Replaces:       "App",
+		ReplacesImport: appImport,
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.ReactRouter },
+	Detect: func() bool {
+		return hasDependency("react-router") || hasDependency("react-router-dom")
+	},
+	check: checkRouter,
+	write: func(plan.Plan) error {
+		return writeRouteFiles(reactRouterFiles)
+	},
+	add: func(plan.Plan) error {
+		return writeRouteFiles(reactRouterFiles)
+	},
+	remove: func(plan.Plan) error {
+		return removeRouteFiles(reactRouterFiles)
+	},
+	verify: func(plan.Plan) []string {
+		return missingRouteFiles("React Router", reactRouterFiles)
+	},
+}
+
+var tanstackRouterFeature = &Feature{
+	Name:    "tanstack-router",
+	Title:   "TanStack Router",
+	Bundler: plan.BundlerVite,
+	Packages: []Package{
+		{Name: "@tanstack/react-router"},
+		{Name: "@tanstack/router-plugin", Dev: true},
+	},
+	Conflicts: []string{"react-router"},
+	Files: []string{
+		"src/router.ts",
+		"src/routeTree.gen.ts",
+		"src/routes/__root.tsx",
+		"src/routes/index.tsx",
+		"src/routes/-not-found.tsx",
+	},
+	Provider: &mainfile.Provider{
+		Element: "RouterProvider",
+		Attrs:   "router={router}",
+		Imports: []mainfile.Import{
+			{Names: []string{"RouterProvider"}, Module: "@tanstack/react-router"},
+			{Names: []string{"router"}, Module: "./router"},
+		},
+		Replaces:       "App",
+		ReplacesImport: appImport,
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.TanStackRouter },
+	Detect: func() bool {
+		return hasDependency("@tanstack/react-router")
+	},
+	check: checkRouter,
+	write: func(plan.Plan) error {
+		return writeRouteFiles(tanstackRouterFiles)
+	},
+	add: func(plan.Plan) error {
+		if err := writeRouteFiles(tanstackRouterFiles); err != nil {
+			return err
+		}
+		return addViteRouterPlugin()
+	},
+	remove: func(plan.Plan) error {
+		if err := removeRouteFiles(tanstackRouterFiles); err != nil {
+			return err
+		}
+		return removeViteRouterPlugin()
+	},
+	verify: func(plan.Plan) []string {
+		issues := missingRouteFiles("TanStack Router", tanstackRouterFiles)
+		if path := viteConfigPath(); path != "" {
+			if data, err := fsys.ReadFile(path); err == nil && !strings.Contains(string(data), "@tanstack/router-plugin") {
+				issues = append(issues, "TanStack Router is installed but "+path+" does not use @tanstack/router-plugin, so src/routeTree.gen.ts is never regenerated")
+			}
+		}
+		return issues
+	},
+}
+
+// existingRouter matches routers wired by hand, which a generated router would fight with.
+var existingRouter = regexp.MustCompile(`<(BrowserRouter|HashRouter|MemoryRouter|RouterProvider)\b`)
+
+// checkRouter refuses to generate routes over a project that already has them.
+func checkRouter(p plan.Plan) (bool, error) {
+	if fileExists(filepath.Join("src", "routes")) {
+		return false, fmt.Errorf("src/routes already exists; move it aside or wire the router into it manually")
+	}
+
+	mainPath := filepath.Join("src", MainEntryFilename(p))
+	data, err := fsys.ReadFile(mainPath)
+	if err != nil {
+		return false, err
+	}
+	if m := existingRouter.FindSubmatch(data); m != nil {
+		return false, fmt.Errorf("%s already renders <%s>; remove it before adding a router", mainPath, m[1])
+	}
+	return true, nil
+}
+
+// routeFile is a generated file and the content it is written with.
+type routeFile struct {
+	path    string
+	content string
+}
+
+// writeRouteFiles writes the router and its routes under src/.
+func writeRouteFiles(files []routeFile) error {
+	if err := fsys.MkdirAll(filepath.Join("src", "routes"), 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := writeFile(filepath.FromSlash(f.path), []byte(f.content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeRouteFiles deletes the generated routes that are unmodified, then
+// src/routes once it is empty. Routes the user has written are kept.
+func removeRouteFiles(files []routeFile) error {
+	var kept []string
+	for _, f := range files {
+		path := filepath.FromSlash(f.path)
+		// The route tree is regenerated from the routes, so it is never the user's.
+		if f.path == "src/routeTree.gen.ts" {
+			if err := removeFile(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := deleteFileIfContentMatches(path, f.content); err != nil {
+			return err
+		}
+		if fileExists(path) {
+			kept = append(kept, f.path)
+		}
+	}
+	if err := removeDirIfEmpty(filepath.Join("src", "routes")); err != nil {
+		return err
+	}
+
+	if len(kept) > 0 {
+		logger.Warning("\nKept modified router files: " + strings.Join(kept, ", ") + ". Delete them once you no longer need them.")
+	}
+	return nil
+}
+
+// missingRouteFiles reports generated routes the router imports but that are gone.
+func missingRouteFiles(title string, files []routeFile) []string {
+	var issues []string
+	for _, f := range files {
+		if !fileExists(filepath.FromSlash(f.path)) {
+			issues = append(issues, title+" is installed but "+f.path+" is missing")
+		}
+	}
+	return issues
+}
+
+var reactRouterFiles = []routeFile{
+	{"src/router.ts", `import { createBrowserRouter } from 'react-router';
+
+import Home from './routes/index';
+import NotFound from './routes/not-found';
+import RootLayout from './routes/root';
+
+export const router = createBrowserRouter([
+  {
+    path: '/',
+    Component: RootLayout,
+    children: [
+      { index: true, Component: Home },
+      { path: '*', Component: NotFound },
+    ],
+  },
+]);
+`},
+	{"src/routes/root.tsx", `import { Outlet } from 'react-router';
+
+export default function RootLayout() {
+  return <Outlet />;
+}
+`},
+	{"src/routes/index.tsx", `import App from '../App';
+
+export default function Home() {
+  return <App />;
+}
+`},
+	{"src/routes/not-found.tsx", `import { Link } from 'react-router';
+
+export default function NotFound() {
+  return (
+    <main>
+      <h1>Page not found</h1>
+      <Link to="/">Back home</Link>
+    </main>
+  );
+}
+`},
+}
+
+// tanstackRouterFiles follow the file-based layout of the router plugin. Files
+// starting with "-" are not routes, and src/routeTree.gen.ts is a seed the
+// plugin overwrites on the first dev or build run, so type checks pass before it.
+var tanstackRouterFiles = []routeFile{
+	{"src/router.ts", `import { createRouter } from '@tanstack/react-router';
+
+import { routeTree } from './routeTree.gen';
+
+export const router = createRouter({ routeTree });
+
+declare module '@tanstack/react-router' {
+  interface Register {
+    router: typeof router;
+  }
+}
+`},
+	{"src/routes/__root.tsx", `import { createRootRoute, Outlet } from '@tanstack/react-router';
+
+import NotFound from './-not-found';
+
+function RootLayout() {
+  return <Outlet />;
+}
+
+export const Route = createRootRoute({
+  component: RootLayout,
+  notFoundComponent: NotFound,
+});
+`},
+	{"src/routes/index.tsx", `import { createFileRoute } from '@tanstack/react-router';
+
+import App from '../App';
+
+export const Route = createFileRoute('/')({
+  component: App,
+});
+`},
+	{"src/routes/-not-found.tsx", `import { Link } from '@tanstack/react-router';
+
+export default function NotFound() {
+  return (
+    <main>
+      <h1>Page not found</h1>
+      <Link to="/">Back home</Link>
+    </main>
+  );
+}
+`},
+	{"src/routeTree.gen.ts", `/* eslint-disable */
+
+// @ts-nocheck
+
+// noinspection JSUnusedGlobalSymbols
+
+// This file was automatically generated by TanStack Router.
+// You should NOT make any changes in this file as it will be overwritten.
+// Additionally, you should also exclude this file from your linter and/or formatter to prevent it from being checked or modified.
+
+import { Route as rootRouteImport } from './routes/__root'
+import { Route as IndexRouteImport } from './routes/index'
+
+const IndexRoute = IndexRouteImport.update({
+  id: '/',
+  path: '/',
+  getParentRoute: () => rootRouteImport,
+} as any)
+
+export interface FileRoutesByFullPath {
+  '/': typeof IndexRoute
+}
+export interface FileRoutesByTo {
+  '/': typeof IndexRoute
+}
+export interface FileRoutesById {
+  __root__: typeof rootRouteImport
+  '/': typeof IndexRoute
+}
+export interface FileRouteTypes {
+  fileRoutesByFullPath: FileRoutesByFullPath
+  fullPaths: '/'
+  fileRoutesByTo: FileRoutesByTo
+  to: '/'
+  id: '__root__' | '/'
+  fileRoutesById: FileRoutesById
+}
+export interface RootRouteChildren {
+  IndexRoute: typeof IndexRoute
+}
+
+declare module '@tanstack/react-router' {
+  interface FileRoutesByPath {
+    '/': {
+      id: '/'
+      path: '/'
+      fullPath: '/'
+      preLoaderRoute: typeof IndexRouteImport
+      parentRoute: typeof rootRouteImport
+    }
+  }
+}
+
+const rootRouteChildren: RootRouteChildren = {
+  IndexRoute: IndexRoute,
+}
+export const routeTree = rootRouteImport
+  ._addFileChildren(rootRouteChildren)
+  ._addFileTypes<FileRouteTypes>()
+`},
+}
+
+// tanstackRouterPlugin generates src/routeTree.gen.ts from src/routes and splits each route into its own chunk.
+const tanstackRouterPlugin = `tanstackRouter({ target: "react", autoCodeSplitting: true })`
+
+var viteRouterImport = regexp.MustCompile(`(?m)^import\s*\{\s*([A-Za-z_$][\w$]*)\s*\}\s*from\s*["']@tanstack/router-plugin/vite["'];?[ \t]*\r?\n`)
+
+// addViteRouterPlugin imports the TanStack Router plugin in the Vite config and
+// puts it first in its plugins, since it must generate the route tree before react() compiles it.
+func addViteRouterPlugin() error {
+	path := viteConfigPath()
+	if path == "" {
+		return fmt.Errorf("vite config not found; add %s from @tanstack/router-plugin/vite manually", tanstackRouterPlugin)
+	}
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	if strings.Contains(content, "@tanstack/router-plugin") {
+		return nil
+	}
+	if generated, ok := generatedViteConfig(content); ok {
+		generated.TanStackRouter = true
+		return writeFile(path, []byte(viteConfigContent(generated)), 0o644)
+	}
+
+	loc := vitePluginsArray.FindStringIndex(content)
+	if loc == nil {
+		return fmt.Errorf("no plugins array found in %s; add %s from @tanstack/router-plugin/vite manually", path, tanstackRouterPlugin)
+	}
+	open := loc[1] - 1
+	close := matchingBracket(content, open)
+	if close < 0 {
+		return fmt.Errorf("unbalanced plugins array in %s; add %s from @tanstack/router-plugin/vite manually", path, tanstackRouterPlugin)
+	}
+	inner := content[open+1 : close]
+	entry := tanstackRouterPlugin
+	switch {
+	case strings.Contains(inner, "\n"):
+		rest := strings.TrimLeft(inner, "\r\n")
+		indent := rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+		entry = "\n" + indent + entry + ","
+	case strings.TrimSpace(inner) != "":
+		entry += ", "
+	}
+	content = content[:open+1] + entry + content[open+1:]
+
+	quote, semicolon := `"`, ";"
+	at := 0
+	if m := viteFirstImport.FindStringSubmatchIndex(content); m != nil {
+		at = m[0]
+		quote, semicolon = content[m[2]:m[3]], content[m[4]:m[5]]
+	}
+	line := "import { tanstackRouter } from " + quote + "@tanstack/router-plugin/vite" + quote + semicolon + "\n"
+	content = content[:at] + line + content[at:]
+
+	return writeFile(path, []byte(content), 0o644)
+}
+
+// removeViteRouterPlugin drops the TanStack Router plugin import and call from the Vite config.
+func removeViteRouterPlugin() error {
+	path := viteConfigPath()
+	if path == "" {
+		return nil
+	}
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	m := viteRouterImport.FindSubmatch(data)
+	if m == nil {
+		return nil
+	}
+	content := viteRouterImport.ReplaceAllString(string(data), "")
+
+	content, ok := removePluginCall(content, string(m[1]))
+	if !ok {
+		logger.Warning("\nCould not find the TanStack Router plugin call in " + path + "; remove it manually.")
+		return nil
+	}
+	return writeFile(path, []byte(content), 0o644)
+}
+
+// removePluginCall deletes the first call to name, arguments included, and the
+// comma separating it from its neighbours in the plugins array.
+func removePluginCall(content, name string) (string, bool) {
+	loc := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\(`).FindStringIndex(content)
+	if loc == nil {
+		return content, false
+	}
+	start := loc[0]
+	end := matchingBracket(content, loc[1]-1)
+	if end < 0 {
+		return content, false
+	}
+	end++
+
+	rest := content[end:]
+	afterComma := strings.TrimLeft(rest, " \t")
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	switch {
+	case strings.HasPrefix(afterComma, ","):
+		end += len(rest) - len(afterComma) + 1
+		tail := content[end:]
+		if trimmed := strings.TrimLeft(tail, " \t"); strings.TrimSpace(content[lineStart:start]) == "" && strings.HasPrefix(strings.TrimPrefix(trimmed, "\r"), "\n") {
+			// The call has a line of its own: drop the whole line.
+			start = lineStart
+			end += strings.Index(tail, "\n") + 1
+		} else {
+			end += len(tail) - len(trimmed)
+		}
+	default:
+		// Last in the array: drop the comma before it instead.
+		before := strings.TrimRight(content[:start], " \t\r\n")
+		if strings.HasSuffix(before, ",") {
+			start = len(before) - 1
+		}
+	}
+	return content[:start] + content[end:], true
+}
@@ -0,0 +1,117 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+func TestTanStackRouter_AddRemoveRoundTrips(t *testing.T) {
+	t.Chdir(t.TempDir())
+	vite := `import react from '@vitejs/plugin-react'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  plugins: [
+    react(),
+  ],
+})
+`
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("vite.config.ts", []byte(vite), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.Plan{Bundler: plan.BundlerVite, TanStackRouter: true}
+	if err := tanstackRouterFeature.add(p); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+
+	wantVite := `import { tanstackRouter } from '@tanstack/router-plugin/vite'
+import react from '@vitejs/plugin-react'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  plugins: [
+    ` + tanstackRouterPlugin + `,
+    react(),
+  ],
+})
+`
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != wantVite {
+		t.Errorf("vite.config.ts =\n%s\nwant\n%s", got, wantVite)
+	}
+	for _, path := range tanstackRouterFeature.Files {
+		if !fileExists(filepath.FromSlash(path)) {
+			t.Errorf("%s not written", path)
+		}
+	}
+
+	edited := filepath.Join("src", "routes", "index.tsx")
+	if err := os.WriteFile(edited, []byte("export const Route = null;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tanstackRouterFeature.remove(plan.Plan{Bundler: plan.BundlerVite}); err != nil {
+		t.Fatalf("remove() error = %v", err)
+	}
+
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != vite {
+		t.Errorf("remove after add left vite.config.ts =\n%s", got)
+	}
+	entries, _ := os.ReadDir(filepath.Join("src", "routes"))
+	if len(entries) != 1 || entries[0].Name() != "index.tsx" {
+		t.Errorf("src/routes = %v, want only the edited index.tsx", entries)
+	}
+	if fileExists(filepath.Join("src", "routeTree.gen.ts")) || fileExists(filepath.Join("src", "router.ts")) {
+		t.Error("generated router files were kept")
+	}
+}
+
+func TestTanStackRouter_RewritesGeneratedViteConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := WriteViteConfig(plan.Plan{Tailwind: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := addViteRouterPlugin(); err != nil {
+		t.Fatalf("addViteRouterPlugin() error = %v", err)
+	}
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != viteConfigContent(plan.Plan{Tailwind: true, TanStackRouter: true}) {
+		t.Errorf("vite.config.ts =\n%s", got)
+	}
+
+	if err := removeViteRouterPlugin(); err != nil {
+		t.Fatalf("removeViteRouterPlugin() error = %v", err)
+	}
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != viteConfigContent(plan.Plan{Tailwind: true}) {
+		t.Errorf("vite.config.ts =\n%s", got)
+	}
+}
+
+func TestCheckRouter_RefusesExistingRouter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	main := "root.render(\n  <BrowserRouter>\n    <App />\n  </BrowserRouter>\n);\n"
+	if err := os.WriteFile(filepath.Join("src", "main.tsx"), []byte(main), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.Plan{Bundler: plan.BundlerVite}
+	if _, err := checkRouter(p); err == nil || !strings.Contains(err.Error(), "<BrowserRouter>") {
+		t.Errorf("checkRouter() error = %v, want a refusal naming BrowserRouter", err)
+	}
+
+	if err := os.Mkdir(filepath.Join("src", "routes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := checkRouter(p); err == nil || !strings.Contains(err.Error(), "src/routes") {
+		t.Errorf("checkRouter() error = %v, want a refusal naming src/routes", err)
+	}
+}
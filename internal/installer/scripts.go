@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+// baseScripts are managed for every scaffold, whatever features it selects.
+var baseScripts = []Script{
+	{Name: "typecheck", Command: "tsc -b", Bundler: plan.BundlerVite},
+	{Name: "typecheck", Command: "tsc --noEmit", Bundler: plan.BundlerBun},
+	bunTestScript,
+}
+
+var bunTestScript = Script{Name: "test", Command: "bun test", Bundler: plan.BundlerBun}
+
+func scriptsFor(scripts []Script, p plan.Plan) []Script {
+	var out []Script
+	for _, s := range scripts {
+		if s.Bundler != "" && s.Bundler != p.Bundler {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// mergeScripts writes the base scripts and those of every selected feature into package.json.
+func mergeScripts(p plan.Plan) error {
+	scripts := scriptsFor(baseScripts, p)
+	for _, f := range features {
+		if f.Enabled(p) {
+			scripts = append(scripts, f.ScriptsFor(p)...)
+		}
+	}
+	return addScripts(scripts)
+}
+
+// addScripts adds the scripts package.json does not define yet; user scripts of the same name are kept.
+func addScripts(scripts []Script) error {
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	return updatePackageJSON(func(pkg *pkgjson.File) error {
+		for _, s := range scripts {
+			if command, ok := pkg.Script(s.Name); ok {
+				if command != s.Command {
+					logger.Info("Keeping your `" + s.Name + "` script (" + command + ").")
+				}
+				continue
+			}
+			if err := pkg.SetScript(s.Name, s.Command); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// removeScripts deletes the scripts that still run the generated command; edited ones are kept.
+func removeScripts(scripts []Script) error {
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	return updatePackageJSON(func(pkg *pkgjson.File) error {
+		for _, s := range scripts {
+			command, ok := pkg.Script(s.Name)
+			if !ok {
+				continue
+			}
+			if command != s.Command {
+				logger.Info("Keeping your `" + s.Name + "` script (" + command + ").")
+				continue
+			}
+			if err := pkg.RemoveScript(s.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
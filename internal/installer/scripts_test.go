@@ -0,0 +1,62 @@
+package installer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/pkgjson"
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+func TestScripts_KeepUserDefinedCommands(t *testing.T) {
+	t.Chdir(t.TempDir())
+	src := `{
+  "name": "app",
+  "scripts": {
+    "dev": "vite",
+    "lint": "eslint src --max-warnings 0"
+  }
+}
+`
+	if err := os.WriteFile("package.json", []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.Plan{Bundler: plan.BundlerVite, Eslint: true, Prettier: true}
+	if err := mergeScripts(p); err != nil {
+		t.Fatalf("mergeScripts() error = %v", err)
+	}
+
+	pkg, err := pkgjson.Load("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"lint":         "eslint src --max-warnings 0",
+		"lint:fix":     "eslint . --fix",
+		"format":       "prettier --write .",
+		"format:check": "prettier --check .",
+		"typecheck":    "tsc -b",
+	}
+	for name, command := range want {
+		if got, _ := pkg.Script(name); got != command {
+			t.Errorf("script %q = %q, want %q", name, got, command)
+		}
+	}
+	if _, ok := pkg.Script("test"); ok {
+		t.Error("Vite scaffold without a test runner got a test script")
+	}
+
+	if err := removeScripts(eslintFeature.ScriptsFor(p)); err != nil {
+		t.Fatalf("removeScripts() error = %v", err)
+	}
+	if pkg, err = pkgjson.Load("package.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pkg.Script("lint:fix"); ok {
+		t.Error("generated lint:fix script survived removal")
+	}
+	if got, _ := pkg.Script("lint"); got != "eslint src --max-warnings 0" {
+		t.Errorf("user lint script = %q after removal, want it kept", got)
+	}
+}
@@ -0,0 +1,110 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/runner"
+)
+
+var shadcnFeature = &Feature{
+	Name:        "shadcn",
+	Title:       "shadcn/ui",
+	FlagUsage:   "Run shadcn-ui init (interactive) on top of Tailwind",
+	AddUsage:    "Run shadcn-ui init (interactive) on top of Tailwind",
+	RemoveUsage: "Uninstall the packages shadcn-ui init added and delete components.json and src/lib/utils.ts if unmodified",
+	Leftovers: []string{
+		"class-variance-authority",
+		"clsx",
+		"tailwind-merge",
+		"lucide-react",
+		"tailwindcss-animate",
+		"tw-animate-css",
+		"@radix-ui/react-icons",
+	},
+	Files:    []string{"components.json", shadcnUtilsPath},
+	Requires: []string{"tailwind"},
+	Selected: func(p *plan.Plan) *bool { return &p.Shadcn },
+	Detect:   func() bool { return fileExists("components.json") },
+	write:    initShadcn,
+	check: func(p plan.Plan) (bool, error) {
+		if _, err := fsys.Stat("components.json"); err == nil {
+			logger.Warning("\ncomponents.json already exists; shadcn/ui looks initialized. Skipping init.")
+			logger.Info("\nUse `" + shadcnCommand(p) + " add <component>` to add components.")
+			return false, nil
+		} else if !os.IsNotExist(err) {
+			return false, err
+		}
+		return true, nil
+	},
+	add:    initShadcn,
+	remove: removeShadcnFiles,
+}
+
+const shadcnUtilsPath = "src/lib/utils.ts"
+
+func initShadcn(p plan.Plan) error {
+	indexExists := true
+	if _, err := fsys.Stat(filepath.Join("src", "index.css")); err != nil {
+		if os.IsNotExist(err) {
+			indexExists = false
+		} else {
+			return err
+		}
+	}
+
+	// shadcn-ui init installs its own dependencies, so it waits for the user's install.
+	if p.NoInstall {
+		logger.Warning("\nSkipping shadcn-ui init because dependencies are not installed. Run `" + shadcnCommand(p) + " init` after installing.")
+		return nil
+	}
+
+	initArgs := []string{"init"}
+	if p.NoPrompt {
+		logger.Info("\nRunning shadcn-ui init with its default configuration...")
+		initArgs = append(initArgs, "--yes", "--defaults")
+	} else {
+		logger.Info("\nRunning shadcn-ui init (you'll see prompts for theme/config)...")
+	}
+	bin, args := p.PackageManager().DlxArgs("shadcn-ui@latest", initArgs...)
+	if err := runner.Run(bin, args...); err != nil {
+		return err
+	}
+	// init writes these itself; tracking them lets `remove shadcn` tell whether they were edited.
+	trackFile("components.json")
+	trackFile(shadcnUtilsPath)
+
+	if !indexExists {
+		logger.Warning("\nshadcn-ui initialized, but src/index.css was not found; ensure your Tailwind entry CSS exists and is wired in your project.")
+	}
+
+	logger.Info("\nshadcn-ui initialized. Add components with `" + shadcnCommand(p) + " add button card input ...`")
+	return nil
+}
+
+func shadcnCommand(p plan.Plan) string {
+	return p.PackageManager().Dlx("shadcn-ui@latest")
+}
+
+// removeShadcnFiles deletes the files shadcn-ui init wrote when .sparky.json shows them unmodified.
+// Components added later and the CSS variables init put in src/index.css are left for the user.
+func removeShadcnFiles(plan.Plan) error {
+	if err := deleteFileIfContentMatches("components.json"); err != nil {
+		return err
+	}
+	if err := deleteFileIfContentMatches(shadcnUtilsPath); err != nil {
+		return err
+	}
+	if err := removeDirIfEmpty(filepath.Dir(shadcnUtilsPath)); err != nil {
+		return err
+	}
+
+	if fileExists("components.json") {
+		logger.Warning("\ncomponents.json was edited or not recorded in .sparky.json; left in place.")
+	}
+	logger.Info("\nshadcn/ui removed. Components under src/components/ui and the theme variables in src/index.css were left untouched.")
+	return nil
+}
@@ -0,0 +1,144 @@
+package installer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hotslug/go-sparky/internal/mainfile"
+	"github.com/hotslug/go-sparky/internal/manifest"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+)
+
+// File states reported by ProjectStatus.
+const (
+	FilePristine = "pristine"
+	FileModified = "modified"
+	FileMissing  = "missing"
+)
+
+// Status is a read-only report on the project in the current directory.
+type Status struct {
+	Bundler        plan.BundlerType `json:"bundler"`
+	BundlerReason  string           `json:"bundlerReason"`
+	PackageManager pm.Manager       `json:"packageManager"`
+	Manifest       bool             `json:"manifest"`          // .sparky.json exists
+	Version        string           `json:"version,omitempty"` // go-sparky version that last wrote the manifest
+	Stack          string           `json:"stack,omitempty"`
+	Stacks         []StackStatus    `json:"stacks"`
+	Files          []FileStatus     `json:"files"`
+	Issues         []string         `json:"issues"`
+}
+
+// StackStatus reports one go-sparky stack found on disk or recorded in the manifest.
+type StackStatus struct {
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	Detected bool   `json:"detected"` // found in package.json or on disk
+	Recorded bool   `json:"recorded"` // listed in .sparky.json
+}
+
+// FileStatus reports whether a generated file still has the content go-sparky wrote.
+type FileStatus struct {
+	Path  string `json:"path"`
+	State string `json:"state"` // FilePristine, FileModified or FileMissing
+}
+
+// ProjectStatus inspects the project in the current directory without changing it.
+func ProjectStatus() (*Status, error) {
+	bundler, reason, err := ExplainBundler()
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Status{
+		Bundler:        bundler,
+		BundlerReason:  reason,
+		PackageManager: DetectPackageManager(bundler),
+		Stacks:         []StackStatus{},
+		Files:          []FileStatus{},
+		Issues:         []string{},
+	}
+	p := plan.Plan{Bundler: bundler, PM: st.PackageManager}
+
+	m, err := manifest.Load()
+	switch {
+	case err == nil:
+		st.Manifest, st.Version, st.Stack = true, m.Version, m.Plan.Stack
+	case os.IsNotExist(err):
+		m = manifest.New(p)
+	default:
+		return nil, err
+	}
+
+	for _, f := range features {
+		detected := f.Detect != nil && f.Detect()
+		recorded := m.HasFeature(f.Name)
+		if !detected && !recorded {
+			continue
+		}
+		st.Stacks = append(st.Stacks, StackStatus{Name: f.Name, Title: f.Title, Detected: detected, Recorded: recorded})
+		if f.Selected != nil {
+			*f.Selected(&p) = detected
+		}
+
+		switch {
+		case recorded && !detected:
+			st.Issues = append(st.Issues, f.Title+" is recorded in "+manifest.Filename+" but no longer detected")
+		case detected && !recorded && st.Manifest:
+			st.Issues = append(st.Issues, f.Title+" is present but not recorded in "+manifest.Filename)
+		}
+	}
+
+	for _, f := range features {
+		if f.Enabled(p) {
+			st.Issues = append(st.Issues, verifyFeature(f, p)...)
+		}
+	}
+
+	paths := make([]string, 0, len(m.Files))
+	for path := range m.Files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		state := FileModified
+		switch {
+		case m.Pristine(path):
+			state = FilePristine
+		case !fileExists(path):
+			state = FileMissing
+		}
+		st.Files = append(st.Files, FileStatus{Path: path, State: state})
+	}
+
+	return st, nil
+}
+
+// verifyFeature reports config an installed feature needs but the project lacks.
+func verifyFeature(f *Feature, p plan.Plan) []string {
+	var issues []string
+	for _, name := range f.Requires {
+		if dep, ok := LookupFeature(name); ok && !dep.Enabled(p) {
+			issues = append(issues, f.Title+" is installed but "+dep.Title+", which it requires, is not")
+		}
+	}
+
+	if f.Provider != nil {
+		mainPath := filepath.Join("src", MainEntryFilename(p))
+		if src, err := readMainEntry(p); err != nil {
+			issues = append(issues, f.Title+" is installed but "+mainPath+" is missing")
+		} else if has, err := mainfile.Has(src, f.Provider); errors.Is(err, mainfile.ErrUnsupported) {
+			issues = append(issues, "Could not check "+mainPath+" for "+f.Provider.Element+": "+err.Error())
+		} else if err == nil && !has {
+			issues = append(issues, f.Title+" is installed but "+mainPath+" does not render "+f.Provider.Element)
+		}
+	}
+
+	if f.verify != nil {
+		issues = append(issues, f.verify(p)...)
+	}
+	return issues
+}
@@ -0,0 +1,56 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+func TestProjectStatus_ReportsMissingProviderAndPostCSS(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"package.json": `{
+  "dependencies": {
+    "@mantine/core": "^8.0.0",
+    "@tanstack/react-query": "^5.0.0",
+    "react": "^19.0.0"
+  }
+}
+`,
+		"vite.config.ts":                 viteConfigContent(plan.Plan{}),
+		filepath.Join("src", "main.tsx"): "import { createRoot } from \"react-dom/client\";\nimport App from \"./App\";\n\ncreateRoot(document.getElementById(\"root\")!).render(<App />);\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := ProjectStatus()
+	if err != nil {
+		t.Fatalf("ProjectStatus() error = %v", err)
+	}
+
+	if st.Bundler != plan.BundlerVite || st.BundlerReason != "vite.config.ts found" {
+		t.Errorf("bundler = %s (%s), want vite (vite.config.ts found)", st.Bundler, st.BundlerReason)
+	}
+	for _, name := range []string{"mantine", "react-query"} {
+		if !slices.ContainsFunc(st.Stacks, func(s StackStatus) bool { return s.Name == name && s.Detected }) {
+			t.Errorf("stacks = %+v, want %s detected", st.Stacks, name)
+		}
+	}
+	for _, want := range []string{
+		"TanStack Query is installed but src/main.tsx does not render QueryClientProvider",
+		"Mantine is installed but postcss.config.cjs is missing, so Mantine styles will not compile",
+	} {
+		if !slices.Contains(st.Issues, want) {
+			t.Errorf("issues = %q, want %q", st.Issues, want)
+		}
+	}
+}
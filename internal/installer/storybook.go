@@ -4,47 +4,120 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
-// InstallStorybook installs Storybook dev dependencies for the chosen bundler.
-func InstallStorybook(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Storybook")
-	framework := "@storybook/react"
-	if p.IsVite() {
-		framework = "@storybook/react-vite"
-	}
-
-	if err := addDependencies(p, true,
-		"storybook@latest",
-		framework+"@latest",
-		"@storybook/addon-essentials@latest",
-		"@storybook/addon-interactions@latest",
-		"@storybook/blocks@latest",
-		"@storybook/test@latest",
-	); err != nil {
-		spin("Failed to install Storybook")
+var storybookFeature = &Feature{
+	Name:        "storybook",
+	Title:       "Storybook",
+	FlagUsage:   "Add Storybook config and dependencies",
+	AddUsage:    "Install Storybook config and dependencies",
+	RemoveUsage: "Uninstall Storybook and delete its config and starter story if unmodified",
+	Packages: []Package{
+		{Name: "storybook", Dev: true},
+		{Name: "@storybook/react-vite", Dev: true, Bundler: plan.BundlerVite},
+		{Name: "@storybook/react", Dev: true, Bundler: plan.BundlerBun},
+		{Name: "@storybook/addon-essentials", Dev: true},
+		{Name: "@storybook/addon-interactions", Dev: true},
+		{Name: "@storybook/blocks", Dev: true},
+		{Name: "@storybook/test", Dev: true},
+	},
+	Scripts: []Script{
+		{Name: "storybook", Command: "storybook dev -p 6006"},
+		{Name: "build-storybook", Command: "storybook build"},
+	},
+	Files: []string{
+		".storybook/main.ts",
+		".storybook/preview.ts",
+		"src/stories/SparkyCard.stories.tsx",
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.Storybook },
+	Detect:   HasStorybookConfig,
+	check: func(p plan.Plan) (bool, error) {
+		if HasStorybookConfig() {
+			logger.Warning("\n.storybook already exists; leaving your Storybook config unchanged.")
+			logger.Info("\nStart it with `" + p.PackageManager().Exec("storybook dev -p 6006") + "` or update your existing config manually.")
+			return false, nil
+		}
+		return true, nil
+	},
+	write: func(p plan.Plan) error {
+		return WriteStorybookConfig(p, true)
+	},
+	add:    addStorybookConfig,
+	remove: removeStorybookFiles,
+	verify: func(plan.Plan) []string {
+		return missingPackages("Storybook", "storybook")
+	},
+}
+
+func addStorybookConfig(p plan.Plan) error {
+	indexCSSExists := true
+	if _, err := fsys.Stat(filepath.Join("src", "index.css")); err != nil {
+		if os.IsNotExist(err) {
+			indexCSSExists = false
+		} else {
+			return err
+		}
+	}
+
+	if err := WriteStorybookConfig(p, indexCSSExists); err != nil {
 		return err
 	}
-	spin("Installed Storybook")
 
+	if !indexCSSExists {
+		logger.Warning("\nsrc/index.css not found; update .storybook/preview.ts to import your global styles if needed.")
+	}
+
+	logger.Info("\nStorybook added. Start it with `" + StorybookCommand(p) + "`.")
 	return nil
 }
 
+// StorybookCommand returns the command that starts Storybook through its package.json script.
+func StorybookCommand(p plan.Plan) string {
+	return p.PackageManager().Run("storybook")
+}
+
 // WriteStorybookConfig writes .storybook config files and a starter story.
 // includeIndexCSS toggles importing src/index.css in preview.ts when it exists.
 func WriteStorybookConfig(p plan.Plan, includeIndexCSS bool) error {
-	if err := os.MkdirAll(".storybook", 0o755); err != nil {
+	if err := fsys.MkdirAll(".storybook", 0o755); err != nil {
+		return err
+	}
+
+	if err := writeFile(filepath.Join(".storybook", "main.ts"), []byte(storybookMainConfig(p)), 0o644); err != nil {
 		return err
 	}
 
+	if err := writeFile(filepath.Join(".storybook", "preview.ts"), []byte(storybookPreview(includeIndexCSS)), 0o644); err != nil {
+		return err
+	}
+
+	storyDir := filepath.Join("src", "stories")
+	if err := fsys.MkdirAll(storyDir, 0o755); err != nil {
+		return err
+	}
+
+	storyPath := filepath.Join(storyDir, "SparkyCard.stories.tsx")
+	if _, err := fsys.Stat(storyPath); err == nil {
+		return nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return writeFile(storyPath, []byte(storybookStory), 0o644)
+}
+
+// storybookMainConfig returns .storybook/main.ts for the bundler's framework.
+func storybookMainConfig(p plan.Plan) string {
 	frameworkName := "@storybook/react"
 	if p.IsVite() {
 		frameworkName = "@storybook/react-vite"
 	}
 
-	mainContent := `import type { StorybookConfig } from "` + frameworkName + `";
+	return `import type { StorybookConfig } from "` + frameworkName + `";
 
 const config: StorybookConfig = {
   stories: ["../src/**/*.mdx", "../src/**/*.stories.@(js|jsx|ts|tsx)"],
@@ -64,7 +137,10 @@ const config: StorybookConfig = {
 
 export default config;
 `
+}
 
+// storybookPreview returns .storybook/preview.ts.
+func storybookPreview(includeIndexCSS bool) string {
 	previewContent := `import type { Preview } from "@storybook/react";
 `
 	if includeIndexCSS {
@@ -87,28 +163,10 @@ const preview: Preview = {
 
 export default preview;
 `
+	return previewContent
+}
 
-	if err := os.WriteFile(filepath.Join(".storybook", "main.ts"), []byte(mainContent), 0o644); err != nil {
-		return err
-	}
-
-	if err := os.WriteFile(filepath.Join(".storybook", "preview.ts"), []byte(previewContent), 0o644); err != nil {
-		return err
-	}
-
-	storyDir := filepath.Join("src", "stories")
-	if err := os.MkdirAll(storyDir, 0o755); err != nil {
-		return err
-	}
-
-	storyPath := filepath.Join(storyDir, "SparkyCard.stories.tsx")
-	if _, err := os.Stat(storyPath); err == nil {
-		return nil
-	} else if err != nil && !os.IsNotExist(err) {
-		return err
-	}
-
-	storyContent := `import type { Meta, StoryObj } from "@storybook/react";
+const storybookStory = `import type { Meta, StoryObj } from "@storybook/react";
 import sparky from "../assets/sparky.png";
 
 type SparkyCardProps = {
@@ -160,11 +218,38 @@ export const BoldCTA: Story = {
 };
 `
 
-	return os.WriteFile(storyPath, []byte(storyContent), 0o644)
+// removeStorybookFiles deletes the Storybook config and starter story when unmodified.
+func removeStorybookFiles(plan.Plan) error {
+	mainConfigs := []string{
+		storybookMainConfig(plan.Plan{Bundler: plan.BundlerVite}),
+		storybookMainConfig(plan.Plan{Bundler: plan.BundlerBun}),
+	}
+	if err := deleteFileIfContentMatches(filepath.Join(".storybook", "main.ts"), mainConfigs...); err != nil {
+		return err
+	}
+	if err := deleteFileIfContentMatches(filepath.Join(".storybook", "preview.ts"), storybookPreview(true), storybookPreview(false)); err != nil {
+		return err
+	}
+	if err := removeDirIfEmpty(".storybook"); err != nil {
+		return err
+	}
+
+	storyDir := filepath.Join("src", "stories")
+	if err := deleteFileIfContentMatches(filepath.Join(storyDir, "SparkyCard.stories.tsx"), storybookStory); err != nil {
+		return err
+	}
+	if err := removeDirIfEmpty(storyDir); err != nil {
+		return err
+	}
+
+	if HasStorybookConfig() {
+		logger.Warning("\n.storybook still holds files you changed or added; delete it once you no longer need them.")
+	}
+	return nil
 }
 
 // HasStorybookConfig checks if .storybook already exists.
 func HasStorybookConfig() bool {
-	_, err := os.Stat(".storybook")
+	_, err := fsys.Stat(".storybook")
 	return err == nil
 }
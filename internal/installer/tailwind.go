@@ -2,35 +2,309 @@ package installer
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
-// InstallTailwind installs Tailwind dependencies.
-func InstallTailwind(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Tailwind CSS")
+var tailwindFeature = &Feature{
+	Name:        "tailwind",
+	Title:       "Tailwind CSS",
+	Default:     true,
+	FlagUsage:   "Skip Tailwind (default installs)",
+	AddUsage:    "Install Tailwind CSS and wire it into the bundler config and src/index.css",
+	RemoveUsage: "Uninstall Tailwind, drop its bundler plugin, and switch src/index.css back to the base styles",
+	Packages: []Package{
+		{Name: "tailwindcss", Dev: true, Bundler: plan.BundlerVite},
+		{Name: "@tailwindcss/vite", Dev: true, Bundler: plan.BundlerVite},
+		{Name: "tailwindcss", Version: "^4", Dev: true, Bundler: plan.BundlerBun},
+		{Name: "bun-plugin-tailwind", Dev: true, Bundler: plan.BundlerBun},
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.Tailwind },
+	Detect:   HasTailwind,
+	setup: func(p plan.Plan) error {
+		if p.IsBun() {
+			return WriteBunConfig(p)
+		}
+		return nil
+	},
+	check:  checkTailwind,
+	add:    addTailwind,
+	remove: removeTailwind,
+	verify: verifyTailwind,
+}
 
-	var args []string
-	if p.IsVite() {
-		args = []string{"tailwindcss@latest", "@tailwindcss/vite@latest"}
-	} else if p.IsBun() {
-		args = []string{"tailwindcss@^4", "bun-plugin-tailwind@latest"}
+// verifyTailwind reports a Tailwind install that the bundler or src/index.css does not load.
+func verifyTailwind(p plan.Plan) []string {
+	var issues []string
+	if p.IsBun() {
+		if data, err := fsys.ReadFile("bunfig.toml"); err != nil || !strings.Contains(string(data), "bun-plugin-tailwind") {
+			issues = append(issues, "Tailwind is installed but bunfig.toml does not load bun-plugin-tailwind")
+		}
+	} else if path := viteConfigPath(); path != "" {
+		if data, err := fsys.ReadFile(path); err == nil && !strings.Contains(string(data), "@tailwindcss/vite") {
+			issues = append(issues, "Tailwind is installed but "+path+" does not use @tailwindcss/vite")
+		}
 	}
 
-	if len(args) == 0 {
-		return fmt.Errorf("unknown bundler for Tailwind install")
+	if data, err := fsys.ReadFile(filepath.Join("src", "index.css")); err == nil && !tailwindCSSImport.Match(data) {
+		issues = append(issues, `Tailwind is installed but src/index.css does not @import "tailwindcss"`)
+	}
+	return issues
+}
+
+// checkTailwind refuses projects configured for Tailwind v3, which go-sparky's v4 setup would break.
+func checkTailwind(p plan.Plan) (bool, error) {
+	if hasTailwindPackage() {
+		logger.Warning("\nTailwind is already declared in package.json; leaving your setup unchanged.")
+		return false, nil
 	}
+	if path := existingFile("tailwind.config.js", "tailwind.config.cjs", "tailwind.config.mjs", "tailwind.config.ts"); path != "" {
+		return false, fmt.Errorf("%s configures Tailwind v3; go-sparky sets up Tailwind v4 through the bundler plugin. Migrate it or delete it first", path)
+	}
+	for _, path := range []string{"postcss.config.js", "postcss.config.cjs", "postcss.config.mjs", "postcss.config.ts"} {
+		if data, err := fsys.ReadFile(path); err == nil && strings.Contains(string(data), "tailwindcss") {
+			return false, fmt.Errorf("%s already loads Tailwind through PostCSS; remove that plugin first", path)
+		}
+	}
+	if p.IsVite() && viteConfigPath() == "" {
+		return false, fmt.Errorf("no vite.config.ts found; add one before installing Tailwind")
+	}
+	return true, nil
+}
 
-	if err := addDependencies(p, true, args...); err != nil {
-		spin("Failed to install Tailwind CSS")
+// addTailwind registers the Tailwind plugin with the bundler and imports Tailwind in src/index.css.
+func addTailwind(p plan.Plan) error {
+	if p.IsBun() {
+		if err := WriteBunConfig(p); err != nil {
+			return err
+		}
+	} else if err := addViteTailwindPlugin(); err != nil {
 		return err
 	}
-	spin("Installed Tailwind CSS")
 
+	if err := addTailwindImport(); err != nil {
+		return err
+	}
+
+	logger.Info("\nTailwind CSS added. App.tsx left untouched.")
+	return nil
+}
+
+// removeTailwind unwires the Tailwind plugin and the Tailwind import in src/index.css.
+func removeTailwind(p plan.Plan) error {
 	if p.IsBun() {
-		return WriteBunConfig(p)
+		if err := RemoveBunTailwindPlugin(); err != nil {
+			return err
+		}
+	} else if err := removeViteTailwindPlugin(); err != nil {
+		return err
+	}
+
+	return resetIndexCSS()
+}
+
+var (
+	viteTailwindImport = regexp.MustCompile(`(?m)^import\s+([A-Za-z_$][\w$]*)\s+from\s+["']@tailwindcss/vite["'];?[ \t]*\r?\n`)
+	tailwindCSSImport  = regexp.MustCompile(`(?m)^[ \t]*@import\s+["']tailwindcss["'][^;\n]*;?[ \t]*\r?\n`)
+)
+
+var (
+	viteFirstImport  = regexp.MustCompile(`(?m)^import\s[^\n]*?(["'])[^"'\n]*["'](;?)`)
+	vitePluginsArray = regexp.MustCompile(`plugins\s*:\s*\[`)
+)
+
+// addViteTailwindPlugin imports @tailwindcss/vite in the Vite config and appends tailwindcss() to its plugins.
+func addViteTailwindPlugin() error {
+	path := viteConfigPath()
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	if strings.Contains(content, "@tailwindcss/vite") {
+		return nil
+	}
+	if generated, ok := generatedViteConfig(content); ok {
+		generated.Tailwind = true
+		return writeFile(path, []byte(viteConfigContent(generated)), 0o644)
+	}
+
+	loc := vitePluginsArray.FindStringIndex(content)
+	if loc == nil {
+		return fmt.Errorf("no plugins array found in %s; add tailwindcss() from @tailwindcss/vite manually", path)
+	}
+	open := loc[1] - 1
+	close := matchingBracket(content, open)
+	if close < 0 {
+		return fmt.Errorf("unbalanced plugins array in %s; add tailwindcss() from @tailwindcss/vite manually", path)
+	}
+	inner := content[open+1 : close]
+	items := strings.TrimRight(inner, " \t\r\n")
+	at := open + 1 + len(items)
+	content = content[:at] + pluginEntry(items, strings.Contains(inner, "\n"), "tailwindcss()") + content[at:]
+
+	quote, semicolon := `"`, ";"
+	at = 0
+	if m := viteFirstImport.FindStringSubmatchIndex(content); m != nil {
+		at = m[0]
+		quote, semicolon = content[m[2]:m[3]], content[m[4]:m[5]]
+	}
+	line := "import tailwindcss from " + quote + "@tailwindcss/vite" + quote + semicolon + "\n"
+	content = content[:at] + line + content[at:]
+
+	return writeFile(path, []byte(content), 0o644)
+}
+
+// matchingBracket returns the offset of the ']' closing the '[' at open, or -1.
+func matchingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '\'', '`':
+			end := strings.IndexByte(s[i+1:], s[i])
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		}
+	}
+	return -1
+}
+
+// pluginEntry returns the text that appends entry to an array whose body,
+// without trailing whitespace, is items; it follows a one-per-line layout.
+func pluginEntry(items string, multiline bool, entry string) string {
+	if !multiline {
+		switch {
+		case strings.TrimSpace(items) == "":
+			return entry
+		case strings.HasSuffix(items, ","):
+			return " " + entry
+		}
+		return ", " + entry
+	}
+
+	indent := "  "
+	if i := strings.LastIndex(items, "\n"); i >= 0 {
+		line := items[i+1:]
+		indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	}
+	if strings.HasSuffix(items, ",") || strings.TrimSpace(items) == "" {
+		return "\n" + indent + entry + ","
+	}
+	return ",\n" + indent + entry
+}
+
+// addTailwindImport imports Tailwind after the leading @import rules of src/index.css.
+func addTailwindImport() error {
+	path := filepath.Join("src", "index.css")
+	data, err := fsys.ReadFile(path)
+	if os.IsNotExist(err) {
+		return writeFile(path, []byte(`@import "tailwindcss";`+"\n"), 0o644)
 	}
+	if err != nil {
+		return err
+	}
+	if tailwindCSSImport.Match(data) {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	at := 0
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "@import") {
+			at = i + 1
+		} else if strings.TrimSpace(line) != "" {
+			break
+		}
+	}
+	lines = append(lines[:at], append([]string{`@import "tailwindcss";` + "\n"}, lines[at:]...)...)
+	return writeFile(path, []byte(strings.Join(lines, "")), 0o644)
+}
+
+// removeViteTailwindPlugin drops the @tailwindcss/vite import and its plugin call from the Vite config.
+func removeViteTailwindPlugin() error {
+	for _, path := range []string{"vite.config.ts", "vite.config.js", "vite.config.mjs"} {
+		data, err := fsys.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		m := viteTailwindImport.FindSubmatch(data)
+		if m == nil {
+			return nil
+		}
+		content := viteTailwindImport.ReplaceAllString(string(data), "")
+
+		name := regexp.QuoteMeta(string(m[1]))
+		call := regexp.MustCompile(`,\s*` + name + `\(\s*\)`)
+		if !call.MatchString(content) {
+			call = regexp.MustCompile(`\b` + name + `\(\s*\)\s*,?[ \t]*(\r?\n[ \t]*)?`)
+		}
+		if !call.MatchString(content) {
+			logger.Warning("\nCould not find the Tailwind plugin call in " + path + "; remove it manually.")
+			return nil
+		}
+		content = call.ReplaceAllString(content, "")
 
+		return writeFile(path, []byte(content), 0o644)
+	}
 	return nil
 }
+
+// RemoveBunTailwindPlugin drops bun-plugin-tailwind from the plugins list in bunfig.toml.
+func RemoveBunTailwindPlugin() error {
+	data, err := fsys.ReadFile("bunfig.toml")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := removeBunfigItem(string(data), "serve.static", "plugins", "bun-plugin-tailwind")
+	if err != nil || content == string(data) {
+		return err
+	}
+	return writeFile("bunfig.toml", []byte(content), 0o644)
+}
+
+// resetIndexCSS writes the base stylesheet over the generated Tailwind one, or
+// drops the Tailwind import from a stylesheet the user has edited.
+func resetIndexCSS() error {
+	path := filepath.Join("src", "index.css")
+	data, err := fsys.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if string(data) == tailwindIndexCSS {
+		return writeFile(path, []byte(baseIndexCSS), 0o644)
+	}
+	if !tailwindCSSImport.Match(data) {
+		return nil
+	}
+
+	logger.Warning("\nRemoved the Tailwind import from src/index.css; utility classes and @apply rules left in your code no longer apply.")
+	return writeFile(path, tailwindCSSImport.ReplaceAll(data, nil), 0o644)
+}
@@ -0,0 +1,149 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+func TestRemoveTailwind_RestoresGeneratedFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteViteConfig(plan.Plan{}); err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := os.ReadFile("vite.config.ts")
+
+	if err := WriteViteConfig(plan.Plan{Tailwind: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("src", "index.css"), []byte(tailwindIndexCSS), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := removeTailwind(plan.Plan{Bundler: plan.BundlerVite}); err != nil {
+		t.Fatalf("removeTailwind() error = %v", err)
+	}
+
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != string(plain) {
+		t.Errorf("vite.config.ts =\n%s\nwant\n%s", got, plain)
+	}
+	if got, _ := os.ReadFile(filepath.Join("src", "index.css")); string(got) != baseIndexCSS {
+		t.Errorf("src/index.css not reset to the base styles:\n%s", got)
+	}
+}
+
+func TestRemoveTailwind_EditsCustomConfigs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	vite := `import tw from '@tailwindcss/vite'
+import react from '@vitejs/plugin-react'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  plugins: [
+    tw(),
+    react(),
+  ],
+})
+`
+	bunfig := "[serve.static]\nplugins = [\"bun-plugin-tailwind\", \"./my-plugin.ts\"]\n"
+	for path, content := range map[string]string{"vite.config.ts": vite, "bunfig.toml": bunfig} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := removeViteTailwindPlugin(); err != nil {
+		t.Fatalf("removeViteTailwindPlugin() error = %v", err)
+	}
+	want := `import react from '@vitejs/plugin-react'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  plugins: [
+    react(),
+  ],
+})
+`
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != want {
+		t.Errorf("vite.config.ts =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := RemoveBunTailwindPlugin(); err != nil {
+		t.Fatalf("RemoveBunTailwindPlugin() error = %v", err)
+	}
+	if got, _ := os.ReadFile("bunfig.toml"); string(got) != "[serve.static]\nplugins = [\"./my-plugin.ts\"]\n" {
+		t.Errorf("bunfig.toml = %q", got)
+	}
+}
+
+func TestAddTailwind_PatchesCustomConfigs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	vite := `import react from '@vitejs/plugin-react'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  plugins: [
+    react(),
+  ],
+})
+`
+	css := "@import url('https://example.com/font.css');\n\nbody { margin: 0; }\n"
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range map[string]string{"vite.config.ts": vite, filepath.Join("src", "index.css"): css} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := addTailwind(plan.Plan{Bundler: plan.BundlerVite, Tailwind: true}); err != nil {
+		t.Fatalf("addTailwind() error = %v", err)
+	}
+
+	wantVite := `import tailwindcss from '@tailwindcss/vite'
+import react from '@vitejs/plugin-react'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  plugins: [
+    react(),
+    tailwindcss(),
+  ],
+})
+`
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != wantVite {
+		t.Errorf("vite.config.ts =\n%s\nwant\n%s", got, wantVite)
+	}
+	wantCSS := "@import url('https://example.com/font.css');\n@import \"tailwindcss\";\n\nbody { margin: 0; }\n"
+	if got, _ := os.ReadFile(filepath.Join("src", "index.css")); string(got) != wantCSS {
+		t.Errorf("src/index.css = %q, want %q", got, wantCSS)
+	}
+
+	if err := removeTailwind(plan.Plan{Bundler: plan.BundlerVite}); err != nil {
+		t.Fatalf("removeTailwind() error = %v", err)
+	}
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != vite {
+		t.Errorf("remove after add left vite.config.ts =\n%s", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join("src", "index.css")); string(got) != css {
+		t.Errorf("remove after add left src/index.css = %q", got)
+	}
+}
+
+func TestCheckTailwind_RefusesV3Config(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, path := range []string{"package.json", "vite.config.ts", "tailwind.config.js"} {
+		if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, err := checkTailwind(plan.Plan{Bundler: plan.BundlerVite}); ok || err == nil {
+		t.Fatalf("checkTailwind() = %v, %v; want a refusal", ok, err)
+	}
+}
@@ -0,0 +1,156 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// safeEntries may already exist in a target directory; the scaffold never writes them.
+var safeEntries = map[string]bool{
+	".git":           true,
+	".gitattributes": true,
+	".editorconfig":  true,
+	".npmrc":         true,
+	".nvmrc":         true,
+	".node-version":  true,
+	".tool-versions": true,
+	".idea":          true,
+	".vscode":        true,
+	".DS_Store":      true,
+	"Thumbs.db":      true,
+}
+
+// ExistingEntries lists the names in dir, or nil when dir does not exist.
+func ExistingEntries(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		if info, statErr := os.Stat(dir); statErr == nil && !info.IsDir() {
+			return nil, fmt.Errorf("%s exists and is not a directory", dir)
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// Conflicts returns the entries a scaffold could overwrite: everything except
+// version control, editor settings, toolchain pins and license files.
+func Conflicts(entries []string) []string {
+	var conflicts []string
+	for _, name := range entries {
+		if !isSafeEntry(name) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	return conflicts
+}
+
+func isSafeEntry(name string) bool {
+	if safeEntries[name] {
+		return true
+	}
+	upper := strings.ToUpper(name)
+	return strings.HasPrefix(upper, "LICENSE") || strings.HasPrefix(upper, "LICENCE") || strings.HasPrefix(upper, "COPYING")
+}
+
+// InEnclosingRepo reports whether dir has no .git of its own but sits inside
+// another git repository, as a package in a monorepo does.
+func InEnclosingRepo(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil {
+		return false
+	}
+
+	for parent := filepath.Dir(abs); ; parent = filepath.Dir(parent) {
+		if _, err := os.Stat(filepath.Join(parent, ".git")); err == nil {
+			return true
+		}
+		if parent == filepath.Dir(parent) {
+			return false
+		}
+	}
+}
+
+// RunInScratchDir runs fn in an empty scratch directory, then copies what it
+// created into dir (an absolute path) and returns there. Starters that refuse
+// non-empty directories run this way; existing files in dir are kept unless
+// the starter writes the same path. The scratch directory is named after dir,
+// since starters such as create-vite take the package name from it.
+func RunInScratchDir(dir string, fn func() error) error {
+	root := filepath.Join(os.TempDir(), fmt.Sprintf("go-sparky-%d", time.Now().UnixNano()))
+	if err := fsys.Mkdir(root, 0o700); err != nil {
+		return err
+	}
+	defer func() { _ = fsys.RemoveAll(root) }()
+
+	scratch := filepath.Join(root, filepath.Base(dir))
+	if err := fsys.Mkdir(scratch, 0o700); err != nil {
+		return err
+	}
+
+	if err := fsys.Chdir(scratch); err != nil {
+		return err
+	}
+	runErr := fn()
+	if err := fsys.Chdir(dir); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	return copyTree(scratch, ".")
+}
+
+// copyTree copies the regular files under src into dst through fsys.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		// Dry runs never create the scratch directory, so there is nothing to copy.
+		if path == src && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return fsys.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return fsys.WriteFile(target, data, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,81 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+func TestConflicts_AllowsSafeEntries(t *testing.T) {
+	entries := []string{".git", ".editorconfig", "LICENSE", "LICENSE.md", ".vscode", "package.json", "src"}
+	if got, want := Conflicts(entries), []string{"package.json", "src"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Conflicts() = %v, want %v", got, want)
+	}
+}
+
+func TestExistingEntries_MissingDirectory(t *testing.T) {
+	entries, err := ExistingEntries(filepath.Join(t.TempDir(), "apps", "web"))
+	if err != nil || entries != nil {
+		t.Fatalf("ExistingEntries() = %v, %v; want nil, nil", entries, err)
+	}
+}
+
+func TestRunInScratchDir_CopiesStarterAndKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile("LICENSE", []byte("MIT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := RunInScratchDir(dir, func() error {
+		if err := fsys.MkdirAll("src", 0o755); err != nil {
+			return err
+		}
+		if err := fsys.WriteFile("package.json", []byte("{}\n"), 0o644); err != nil {
+			return err
+		}
+		return fsys.WriteFile(filepath.Join("src", "main.tsx"), []byte("// entry\n"), 0o644)
+	})
+	if err != nil {
+		t.Fatalf("RunInScratchDir() error = %v", err)
+	}
+
+	if wd, _ := os.Getwd(); wd != dir {
+		t.Fatalf("working directory = %s, want %s", wd, dir)
+	}
+	for _, path := range []string{"LICENSE", "package.json", filepath.Join("src", "main.tsx")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s missing after RunInScratchDir: %v", path, err)
+		}
+	}
+}
+
+func TestRunInScratchDir_StarterNamesPackageAfterTarget(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "apps", "web")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	if err := os.WriteFile("LICENSE", []byte("MIT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// create-vite names the package after the directory it runs in.
+	err := RunInScratchDir(dir, func() error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		return fsys.WriteFile("package.json", []byte(`{"name": "`+filepath.Base(wd)+`"}`), 0o644)
+	})
+	if err != nil {
+		t.Fatalf("RunInScratchDir() error = %v", err)
+	}
+
+	if got, _ := os.ReadFile("package.json"); string(got) != `{"name": "web"}` {
+		t.Errorf("package.json = %s, want the package named web", got)
+	}
+}
@@ -2,16 +2,36 @@ package installer
 
 import (
 	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/pkgjson"
 	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
 )
 
-// InstallViteReactPlugin ensures @vitejs/plugin-react is installed to match our Vite config template.
-func InstallViteReactPlugin(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Vite React plugin")
-	if err := addDependencies(p, true, "@vitejs/plugin-react@latest"); err != nil {
-		spin("Failed to install Vite React plugin")
+// vitePackages are installed on top of the create-vite starter to match our Vite config template.
+var vitePackages = []Package{{Name: "@vitejs/plugin-react", Dev: true}}
+
+// CreateViteArgs returns the command that runs the React + TypeScript
+// create-vite starter into the current directory, at the version the plan's
+// stack pins or the latest one.
+func CreateViteArgs(p plan.Plan) (string, []string) {
+	version, _ := loadStack(p).Range("create-vite")
+	return p.PackageManager().CreateArgs("vite", version, "--template", "react-ts")
+}
+
+// WriteYarnrc pins Yarn Berry to a node_modules install so generated configs resolve
+// packages normally, and records the yarn on PATH in packageManager so corepack,
+// such as the one in the generated Dockerfile, runs the same Berry release.
+func WriteYarnrc() error {
+	if err := writeFile(".yarnrc.yml", []byte("nodeLinker: node-modules\n"), 0o644); err != nil {
 		return err
 	}
-	spin("Installed Vite React plugin")
-	return nil
+
+	version, err := pm.YarnVersion()
+	if err != nil {
+		logger.Warning("\nCould not read the yarn version (" + err.Error() + "); set \"packageManager\" in package.json so corepack picks Yarn Berry.")
+		return nil
+	}
+	return updatePackageJSON(func(pkg *pkgjson.File) error {
+		return pkg.SetField("packageManager", "yarn@"+version)
+	})
 }
@@ -0,0 +1,232 @@
+package installer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+var vitestFeature = &Feature{
+	Name:        "vitest",
+	Title:       "Vitest",
+	FlagUsage:   "Add Vitest + Testing Library with a starter test (Vite only)",
+	AddUsage:    "Install Vitest + Testing Library, configure jsdom and write a starter App test",
+	RemoveUsage: "Uninstall Vitest and delete its config, setup file and starter test if unmodified",
+	Bundler:     plan.BundlerVite,
+	Packages: []Package{
+		{Name: "vitest", Dev: true},
+		{Name: "jsdom", Dev: true},
+		{Name: "@testing-library/react", Dev: true},
+		{Name: "@testing-library/dom", Dev: true},
+		{Name: "@testing-library/jest-dom", Dev: true},
+		{Name: "@testing-library/user-event", Dev: true},
+	},
+	Scripts: []Script{
+		{Name: "test", Command: "vitest"},
+	},
+	Files: []string{
+		"src/test/setup.ts",
+		"src/App.test.tsx",
+		"vitest.config.ts",
+	},
+	Selected: func(p *plan.Plan) *bool { return &p.Vitest },
+	Detect:   HasVitest,
+	write: func(p plan.Plan) error {
+		return writeVitestFiles(templates.AppTest(p))
+	},
+	add:    addVitest,
+	remove: removeVitest,
+	verify: verifyVitest,
+}
+
+// vitestTestBlock is the test section of a generated vite.config.ts. globals
+// lets Testing Library clean up the DOM after each test on its own.
+const vitestTestBlock = `  test: {
+    environment: "jsdom",
+    globals: true,
+    setupFiles: "./src/test/setup.ts",
+  },
+`
+
+// vitestConfigContent configures Vitest next to a Vite config go-sparky did not
+// generate, merging the test settings into it instead of editing it.
+const vitestConfigContent = `import { defineConfig, mergeConfig } from "vitest/config";
+
+import viteConfig from "./vite.config";
+
+export default mergeConfig(
+  viteConfig,
+  defineConfig({
+    test: {
+      environment: "jsdom",
+      globals: true,
+      setupFiles: "./src/test/setup.ts",
+    },
+  }),
+);
+`
+
+// HasVitest reports whether package.json declares vitest.
+func HasVitest() bool {
+	return hasDependency("vitest")
+}
+
+// writeVitestFiles writes the test setup file and, unless one exists, the starter App test.
+func writeVitestFiles(appTest string) error {
+	if err := fsys.MkdirAll(filepath.Join("src", "test"), 0o755); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join("src", "test", "setup.ts"), []byte(templates.VitestSetup), 0o644); err != nil {
+		return err
+	}
+
+	testPath := filepath.Join("src", "App.test.tsx")
+	if fileExists(testPath) {
+		return nil
+	}
+	return writeFile(testPath, []byte(appTest), 0o644)
+}
+
+// appTestPlans returns the plans whose App.tsx templates have a starter test of their own.
+func appTestPlans(bundler plan.BundlerType) []plan.Plan {
+	return []plan.Plan{
+		{Bundler: bundler},
+		{Bundler: bundler, Zustand: true},
+		{Bundler: bundler, Mantine: true, StyledApp: true},
+	}
+}
+
+// existingAppTest picks the starter test matching src/App.tsx, or a smoke test
+// when the app is no longer a generated template.
+func existingAppTest(bundler plan.BundlerType) string {
+	data, err := fsys.ReadFile(filepath.Join("src", "App.tsx"))
+	if err == nil {
+		for _, p := range appTestPlans(bundler) {
+			if string(data) == templates.AppTemplate(p) {
+				return templates.AppTest(p)
+			}
+		}
+	}
+	return templates.GenericAppTest(plan.Plan{Bundler: bundler})
+}
+
+// deleteAppTestIfOwned deletes src/App.test.tsx when it is one of the starter tests.
+func deleteAppTestIfOwned(bundler plan.BundlerType) error {
+	tests := []string{templates.GenericAppTest(plan.Plan{Bundler: bundler})}
+	for _, p := range appTestPlans(bundler) {
+		tests = append(tests, templates.AppTest(p))
+	}
+	return deleteFileIfContentMatches(filepath.Join("src", "App.test.tsx"), tests...)
+}
+
+func addVitest(p plan.Plan) error {
+	if err := writeVitestFiles(existingAppTest(plan.BundlerVite)); err != nil {
+		return err
+	}
+	if err := addVitestConfig(); err != nil {
+		return err
+	}
+	if err := setESLintTestGlobals(true); err != nil {
+		return err
+	}
+
+	logger.Info("\nVitest added. Run `" + p.PackageManager().Run("test") + "`.")
+	return nil
+}
+
+// addVitestConfig adds the test block to a generated vite.config.ts, or writes
+// vitest.config.ts on top of a Vite config the user has written.
+func addVitestConfig() error {
+	path := viteConfigPath()
+	if path == "" {
+		logger.Warning("\nvite.config.ts not found; add a Vitest test block with environment \"jsdom\" and setupFiles \"./src/test/setup.ts\" manually.")
+		return nil
+	}
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if generated, ok := generatedViteConfig(string(data)); ok {
+		if generated.Vitest {
+			return nil
+		}
+		generated.Vitest = true
+		return writeFile(path, []byte(viteConfigContent(generated)), 0o644)
+	}
+
+	if existingFile("vitest.config.ts", "vitest.config.js", "vitest.config.mts") != "" || strings.Contains(string(data), "test:") {
+		logger.Warning("\nVitest is already configured; make sure it uses environment \"jsdom\" and setupFiles \"./src/test/setup.ts\".")
+		return nil
+	}
+	return writeFile("vitest.config.ts", []byte(vitestConfigContent), 0o644)
+}
+
+func removeVitest(plan.Plan) error {
+	if err := deleteFileIfContentMatches(filepath.Join("src", "test", "setup.ts"), templates.VitestSetup); err != nil {
+		return err
+	}
+	if err := removeDirIfEmpty(filepath.Join("src", "test")); err != nil {
+		return err
+	}
+
+	if err := deleteAppTestIfOwned(plan.BundlerVite); err != nil {
+		return err
+	}
+
+	if err := deleteFileIfContentMatches("vitest.config.ts", vitestConfigContent); err != nil {
+		return err
+	}
+	if path := viteConfigPath(); path != "" {
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if generated, ok := generatedViteConfig(string(data)); ok && generated.Vitest {
+			generated.Vitest = false
+			if err := writeFile(path, []byte(viteConfigContent(generated)), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return setESLintTestGlobals(false)
+}
+
+// setESLintTestGlobals switches a generated eslint.config.js to the variant
+// with or without Vitest globals; a customized config is left to the user.
+func setESLintTestGlobals(enable bool) error {
+	ok, err := rewriteESLintConfig(func(p *plan.Plan) { p.Vitest = enable })
+	if err != nil || ok {
+		return err
+	}
+
+	data, err := fsys.ReadFile("eslint.config.js")
+	if err == nil && enable && !strings.Contains(string(data), "globals.vitest") {
+		logger.Info("\neslint.config.js is customized; add ...globals.vitest to the globals of *.test.tsx files if ESLint flags describe or expect.")
+	}
+	return nil
+}
+
+// verifyVitest reports a Vitest install that no config points at the setup file.
+func verifyVitest(plan.Plan) []string {
+	var issues []string
+	if !fileExists(filepath.Join("src", "test", "setup.ts")) {
+		issues = append(issues, "Vitest is installed but src/test/setup.ts is missing, so jest-dom matchers are not registered")
+	}
+
+	configured := existingFile("vitest.config.ts", "vitest.config.js", "vitest.config.mts") != ""
+	if path := viteConfigPath(); path != "" && !configured {
+		if data, err := fsys.ReadFile(path); err == nil && strings.Contains(string(data), "test:") {
+			configured = true
+		}
+	}
+	if !configured {
+		issues = append(issues, "Vitest is installed but neither vite.config.ts nor vitest.config.ts has a test block, so tests run without jsdom")
+	}
+	return issues
+}
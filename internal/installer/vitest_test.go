@@ -0,0 +1,98 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/templates"
+)
+
+func TestVitest_AddRemoveGeneratedConfigs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	p := plan.Plan{Bundler: plan.BundlerVite, Tailwind: true, Zustand: true}
+	if err := WriteViteConfig(p); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"eslint.config.js":              templates.EslintConfigRelaxed(p),
+		filepath.Join("src", "App.tsx"): templates.AppTemplate(p),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := addVitest(p); err != nil {
+		t.Fatalf("addVitest() error = %v", err)
+	}
+
+	withVitest := p
+	withVitest.Vitest = true
+	want := map[string]string{
+		"vite.config.ts":                         viteConfigContent(withVitest),
+		"eslint.config.js":                       templates.EslintConfigRelaxed(withVitest),
+		filepath.Join("src", "App.test.tsx"):     templates.AppTest(p),
+		filepath.Join("src", "test", "setup.ts"): templates.VitestSetup,
+	}
+	for path, content := range want {
+		if got, _ := os.ReadFile(path); string(got) != content {
+			t.Errorf("%s =\n%s\nwant\n%s", path, got, content)
+		}
+	}
+	if fileExists("vitest.config.ts") {
+		t.Error("vitest.config.ts written next to a generated vite.config.ts")
+	}
+
+	if err := removeVitest(p); err != nil {
+		t.Fatalf("removeVitest() error = %v", err)
+	}
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != viteConfigContent(p) {
+		t.Errorf("vite.config.ts after remove =\n%s", got)
+	}
+	if got, _ := os.ReadFile("eslint.config.js"); string(got) != templates.EslintConfigRelaxed(p) {
+		t.Errorf("eslint.config.js still declares test globals after remove")
+	}
+	if fileExists(filepath.Join("src", "test")) || fileExists(filepath.Join("src", "App.test.tsx")) {
+		t.Error("generated test files were kept")
+	}
+}
+
+func TestVitest_CustomViteConfigGetsVitestConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+	vite := "import { defineConfig } from 'vite'\n\nexport default defineConfig({})\n"
+	if err := os.Mkdir("src", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("vite.config.ts", []byte(vite), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("src", "App.tsx"), []byte("export default function App() { return <p>hi</p>; }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := addVitest(plan.Plan{Bundler: plan.BundlerVite}); err != nil {
+		t.Fatalf("addVitest() error = %v", err)
+	}
+	if got, _ := os.ReadFile("vite.config.ts"); string(got) != vite {
+		t.Errorf("custom vite.config.ts was edited:\n%s", got)
+	}
+	if got, _ := os.ReadFile("vitest.config.ts"); string(got) != vitestConfigContent {
+		t.Errorf("vitest.config.ts =\n%s", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join("src", "App.test.tsx")); string(got) != templates.GenericAppTest(plan.Plan{Bundler: plan.BundlerVite}) {
+		t.Errorf("App.test.tsx for a custom App =\n%s", got)
+	}
+
+	if err := removeVitest(plan.Plan{Bundler: plan.BundlerVite}); err != nil {
+		t.Fatalf("removeVitest() error = %v", err)
+	}
+	if fileExists("vitest.config.ts") {
+		t.Error("vitest.config.ts kept after remove")
+	}
+}
@@ -1,15 +1,18 @@
 package installer
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/hotslug/go-sparky/internal/fsys"
 	"github.com/hotslug/go-sparky/internal/logger"
 	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/templates"
 )
 
 const zustandStorePath = "src/stores/useSparkyStore.ts"
-
 const zustandStoreContent = `import { create } from 'zustand';
 
 type SparkyState = {
@@ -50,25 +53,66 @@ export const useSparkyStore = create<SparkyStore>((set) => ({
 }));
 `
 
-// InstallZustand installs Zustand dependency.
-func InstallZustand(p plan.Plan) error {
-	spin := logger.StartSpinner("Installing Zustand")
-	if err := addDependencies(p, false, "zustand@latest"); err != nil {
-		spin("Failed to install Zustand")
-		return err
-	}
-	spin("Installed Zustand")
-	return nil
+var zustandFeature = &Feature{
+	Name:        "zustand",
+	Title:       "Zustand",
+	Default:     true,
+	FlagUsage:   "Skip Zustand (default installs)",
+	AddUsage:    "Install Zustand and add a starter store (App.tsx untouched)",
+	RemoveUsage: "Uninstall Zustand and remove the starter store if unmodified",
+	Packages:    []Package{{Name: "zustand"}},
+	Files:       []string{zustandStorePath},
+	Selected:    func(p *plan.Plan) *bool { return &p.Zustand },
+	Detect:      HasZustandDependency,
+	add: func(plan.Plan) error {
+		created, err := WriteZustandStoreIfMissing()
+		if err != nil {
+			return err
+		}
+
+		if created {
+			logger.Info("\nZustand installed. Added src/stores/useSparkyStore.ts. App.tsx left untouched.")
+			return nil
+		}
+
+		logger.Info("\nZustand installed. src/stores/useSparkyStore.ts already exists; left untouched. App.tsx left untouched.")
+		return nil
+	},
+	remove: removeZustandFiles,
 }
 
-// RemoveZustand uninstalls Zustand.
-func RemoveZustand(p plan.Plan) error {
-	spin := logger.StartSpinner("Removing Zustand")
-	if err := removeDependencies(p, false, "zustand"); err != nil {
-		spin("Failed to remove Zustand")
+// removeZustandFiles resets the generated App template and deletes the demo store when untouched.
+func removeZustandFiles(p plan.Plan) error {
+	appPath := filepath.Join("src", "App.tsx")
+	appContent, err := fsys.ReadFile(appPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("src/App.tsx not found. Run this in a go-sparky project")
+		}
 		return err
 	}
-	spin("Removed Zustand")
+
+	appMatchesGenerated := string(appContent) == templates.AppTemplate(plan.Plan{Zustand: true})
+	appUsesStore := bytes.Contains(appContent, []byte("useSparkyStore"))
+
+	switch {
+	case appMatchesGenerated:
+		if err := WriteAppFile(plan.Plan{}); err != nil {
+			return err
+		}
+		if err := DeleteZustandStoreIfOwned(); err != nil {
+			return err
+		}
+		logger.Info("\nZustand removed. src/App.tsx reset to the basic template and demo store deleted.")
+	case !appUsesStore:
+		if err := DeleteZustandStoreIfOwned(); err != nil {
+			return err
+		}
+		logger.Info("\nZustand removed. App.tsx left untouched.")
+	default:
+		logger.Warning("\nZustand removed, but src/App.tsx still references useSparkyStore; update your state to avoid missing imports.")
+	}
+
 	return nil
 }
 
@@ -85,18 +129,18 @@ func WriteZustandStoreIfMissing() (bool, error) {
 
 func writeZustandStore(skipIfExists bool) (bool, error) {
 	if skipIfExists {
-		if _, err := os.Stat(zustandStorePath); err == nil {
+		if _, err := fsys.Stat(zustandStorePath); err == nil {
 			return false, nil
 		} else if !os.IsNotExist(err) {
 			return false, err
 		}
 	}
 
-	if err := os.MkdirAll(filepath.Dir(zustandStorePath), 0o755); err != nil {
+	if err := fsys.MkdirAll(filepath.Dir(zustandStorePath), 0o755); err != nil {
 		return false, err
 	}
 
-	if err := os.WriteFile(zustandStorePath, []byte(zustandStoreContent), 0o644); err != nil {
+	if err := writeFile(zustandStorePath, []byte(zustandStoreContent), 0o644); err != nil {
 		return false, err
 	}
 
@@ -105,22 +149,12 @@ func writeZustandStore(skipIfExists bool) (bool, error) {
 
 // DeleteZustandStoreIfOwned removes the demo store when it matches the generated content.
 func DeleteZustandStoreIfOwned() error {
-	data, err := os.ReadFile(zustandStorePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
+	if err := deleteFileIfContentMatches(zustandStorePath, zustandStoreContent); err != nil {
 		return err
 	}
 
-	if string(data) != zustandStoreContent {
-		return nil
+	if _, err := fsys.Stat(zustandStorePath); os.IsNotExist(err) {
+		_ = fsys.Remove(filepath.Dir(zustandStorePath))
 	}
-
-	if err := os.Remove(zustandStorePath); err != nil {
-		return err
-	}
-
-	_ = os.Remove(filepath.Dir(zustandStorePath))
 	return nil
 }
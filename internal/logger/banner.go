@@ -8,12 +8,12 @@ import (
 //go:embed sparky.txt
 var sparkyBanner string
 
-// PrintBanner renders the ASCII banner to stdout.
+// PrintBanner renders the ASCII banner to the logger output.
 func PrintBanner() {
 	if sparkyBanner == "" {
-		fmt.Println("go-sparky")
+		fmt.Fprintln(out, "go-sparky")
 		return
 	}
 
-	fmt.Println(sparkyBanner)
+	fmt.Fprintln(out, sparkyBanner)
 }
@@ -1,35 +1,47 @@
 package logger
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
-var verbose bool
+var (
+	verbose bool
+	out     io.Writer = os.Stdout
+)
 
 // SetVerbose toggles verbose output helpers (e.g., spinner).
 func SetVerbose(v bool) {
 	verbose = v
 }
 
+// SetOutput redirects every logger helper (and the banner) to w.
+func SetOutput(w io.Writer) {
+	out = w
+}
+
 // Step prints a human-friendly progress message.
 func Step(msg string) {
-	fmt.Printf("\033[1;36m•\033[0m %s\n", msg)
+	fmt.Fprintf(out, "\033[1;36m•\033[0m %s\n", msg)
 }
 
 // Info prints informational text.
 func Info(msg string) {
-	fmt.Println(msg)
+	fmt.Fprintln(out, msg)
 }
 
 // Success prints a success message.
 func Success(msg string) {
-	fmt.Printf("\033[32m✅ %s\033[0m\n", msg)
+	fmt.Fprintf(out, "\033[32m✅ %s\033[0m\n", msg)
 }
 
 // Error prints an error message.
 func Error(msg string) {
-	fmt.Printf("\033[31m✖️  %s\033[0m\n", msg)
+	fmt.Fprintf(out, "\033[31m✖️  %s\033[0m\n", msg)
 }
 
 // Warning prints a warning message.
 func Warning(msg string) {
-	fmt.Printf("\033[33m⚠️  %s\033[0m\n", msg)
+	fmt.Fprintf(out, "\033[33m⚠️  %s\033[0m\n", msg)
 }
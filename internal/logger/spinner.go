@@ -24,7 +24,7 @@ func StartSpinner(msg string) func(finalMsg string) {
 			case <-stop:
 				return
 			default:
-				fmt.Printf("\r\033[2K%s%s\033[0m %s", color, frames[i%len(frames)], msg)
+				fmt.Fprintf(out, "\r\033[2K%s%s\033[0m %s", color, frames[i%len(frames)], msg)
 				i++
 				time.Sleep(120 * time.Millisecond)
 			}
@@ -33,6 +33,6 @@ func StartSpinner(msg string) func(finalMsg string) {
 
 	return func(finalMsg string) {
 		close(stop)
-		fmt.Printf("\r\033[2K\033[32m✓ %s\033[0m\n", finalMsg)
+		fmt.Fprintf(out, "\r\033[2K\033[32m✓ %s\033[0m\n", finalMsg)
 	}
 }
@@ -0,0 +1,634 @@
+// Package mainfile adds and removes React providers in an existing entry file
+// (src/main.tsx or src/frontend.tsx) without regenerating it.
+//
+// It understands the import list and the JSX tree passed to root.render(...).
+// Everything else in the file (routers, auth providers, Sentry init, comments)
+// is left byte-for-byte intact. Files whose shape it cannot follow are
+// reported with ErrUnsupported instead of being rewritten.
+package mainfile
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupported reports an entry file too unusual to edit safely.
+var ErrUnsupported = errors.New("entry file shape not supported")
+
+// Import is a set of imports from one module.
+type Import struct {
+	Default string // default binding, e.g. "App"
+	Names   []string
+	Module  string
+}
+
+// Provider describes a React provider element and what it needs in the file.
+type Provider struct {
+	Element string      // JSX element name, e.g. "MantineProvider"
+	Attrs   string      // attributes written on insert, e.g. "client={queryClient}"
+	Imports []Import    // imports the provider needs
+	Setup   string      // top-level declaration the attributes use, e.g. "const queryClient = new QueryClient();"
+	Extras  []string    // self-closing elements placed after the children, e.g. "<ReactQueryDevtools initialIsOpen={false} />"
+	Inside  []*Provider // providers that stay outside this one when both are present
+
+	// Replaces is the self-closing element a childless provider renders in
+	// place of, e.g. "App" for a router whose routes render the app.
+	// ReplacesImport is that element's import, restored when the provider is removed.
+	Replaces       string
+	ReplacesImport Import
+}
+
+// passThrough elements are descended into when looking for where a provider goes.
+var passThrough = map[string]bool{"React.StrictMode": true, "StrictMode": true}
+
+// Has reports whether the rendered tree contains the provider.
+func Has(src []byte, p *Provider) (bool, error) {
+	root, err := findRender(src)
+	if err != nil {
+		return false, err
+	}
+	return root.find(p.Element) != nil, nil
+}
+
+// Insert wraps the rendered tree in the provider, as far out as possible but
+// inside StrictMode and the providers listed in p.Inside, and adds its
+// imports and setup declaration.
+func Insert(src []byte, p *Provider) ([]byte, error) {
+	root, err := findRender(src)
+	if err != nil {
+		return nil, err
+	}
+	if root.find(p.Element) != nil {
+		return src, nil
+	}
+	if p.Replaces != "" {
+		return replaceElement(src, root, p)
+	}
+
+	// Descend through StrictMode and the providers that belong outside p,
+	// then wrap whatever sits below them.
+	wrap, enclosing := []*node{root}, root
+	for target := root; descends(p, target); {
+		children := significant(src, target.children)
+		if outer := insideOf(p, target.name); outer != nil {
+			children = trimExtras(children, outer)
+		}
+		if len(children) == 0 {
+			return nil, unsupported("<%s> has no children to wrap", target.name)
+		}
+		if len(children) == 1 && descends(p, children[0]) {
+			target = children[0]
+			continue
+		}
+		for _, child := range children {
+			if descends(p, child) {
+				return nil, unsupported("<%s> has siblings, so <%s> cannot be placed inside it", child.name, p.Element)
+			}
+		}
+		wrap, enclosing = children, target
+		break
+	}
+
+	open := "<" + p.Element
+	if p.Attrs != "" {
+		open += " " + p.Attrs
+	}
+	open += ">"
+	closing := "</" + p.Element + ">"
+
+	start, end := wrap[0].start, wrap[len(wrap)-1].end
+	segment := string(src[start:end])
+	indent, leading := lineIndent(src, start)
+
+	var replacement string
+	if leading {
+		unit := indentUnit(src, wrap[0], enclosing)
+		var b strings.Builder
+		b.WriteString(open + "\n" + indent + unit + indentLines(segment, unit))
+		for _, extra := range p.Extras {
+			b.WriteString("\n" + indent + unit + extra)
+		}
+		b.WriteString("\n" + indent + closing)
+		replacement = b.String()
+	} else {
+		replacement = open + segment + strings.Join(p.Extras, "") + closing
+	}
+
+	return addDeclarations(splice(src, start, end, replacement), p)
+}
+
+// replaceElement renders the provider in place of the p.Replaces element and
+// drops that element's import once nothing else uses it.
+func replaceElement(src []byte, root *node, p *Provider) ([]byte, error) {
+	el := root.find(p.Replaces)
+	if el == nil {
+		return nil, unsupported("<%s /> not found in the rendered tree", p.Replaces)
+	}
+	if len(significant(src, el.children)) > 0 {
+		return nil, unsupported("<%s> has children, so <%s /> cannot take its place", p.Replaces, p.Element)
+	}
+
+	tag := "<" + p.Element
+	if p.Attrs != "" {
+		tag += " " + p.Attrs
+	}
+	out, err := removeImport(splice(src, el.start, el.end, tag+" />"), p.ReplacesImport)
+	if err != nil {
+		return nil, err
+	}
+	return addDeclarations(out, p)
+}
+
+// addDeclarations adds the provider's setup declaration and imports.
+func addDeclarations(out []byte, p *Provider) ([]byte, error) {
+	var err error
+	if p.Setup != "" && !declares(out, setupName(p.Setup)) {
+		if out, err = insertSetup(out, p.Setup); err != nil {
+			return nil, err
+		}
+	}
+	for _, imp := range p.Imports {
+		if out, err = addImport(out, imp); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// InsertBeforeRoot inserts code at the start of the top-level statement that
+// first looks up, creates or renders the root, so it runs before the first render.
+func InsertBeforeRoot(src []byte, code string) ([]byte, error) {
+	at, err := rootStatement(src)
+	if err != nil {
+		return nil, err
+	}
+	return splice(src, at, at, code), nil
+}
+
+// Remove unwraps the provider, keeping its children, and drops its extras,
+// setup declaration and imports once nothing else uses them.
+func Remove(src []byte, p *Provider) ([]byte, error) {
+	root, err := findRender(src)
+	if err != nil {
+		return nil, err
+	}
+	el := root.find(p.Element)
+	if el == nil {
+		return src, nil
+	}
+	if p.Replaces != "" {
+		if len(significant(src, el.children)) > 0 {
+			return nil, unsupported("<%s> has children, so <%s /> cannot take its place", p.Element, p.Replaces)
+		}
+		out, err := removeDeclarations(splice(src, el.start, el.end, "<"+p.Replaces+" />"), p)
+		if err != nil {
+			return nil, err
+		}
+		return addImport(out, p.ReplacesImport)
+	}
+
+	children := trimExtras(significant(src, el.children), p)
+	for _, child := range children {
+		if child.kind == elementNode && isExtra(p, child.name) {
+			return nil, unsupported("<%s> sits between the children of <%s>", child.name, p.Element)
+		}
+	}
+	if len(children) == 0 {
+		return nil, unsupported("<%s> has no children to keep", p.Element)
+	}
+	if el == root && len(children) > 1 {
+		return nil, unsupported("<%s> renders several children; unwrapping it would need a fragment", p.Element)
+	}
+
+	segment := string(src[children[0].start:children[len(children)-1].end])
+	if unit := indentUnit(src, children[0], el); unit != "" {
+		segment = strings.ReplaceAll(segment, "\n"+unit, "\n")
+	}
+	return removeDeclarations(splice(src, el.start, el.end, segment), p)
+}
+
+// removeDeclarations drops the provider's setup declaration and imports once nothing else uses them.
+func removeDeclarations(out []byte, p *Provider) ([]byte, error) {
+	var err error
+	if p.Setup != "" {
+		if name := setupName(p.Setup); name != "" && !references(out, name, true) {
+			out = removeDeclaration(out, name)
+		}
+	}
+	for _, imp := range p.Imports {
+		if out, err = removeImport(out, imp); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// descends reports whether n is StrictMode or a provider that stays outside p.
+func descends(p *Provider, n *node) bool {
+	return n.kind == elementNode && (passThrough[n.name] || insideOf(p, n.name) != nil)
+}
+
+// indentLines indents every line after the first by unit, leaving blank lines empty.
+func indentLines(segment, unit string) string {
+	lines := strings.Split(segment, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			lines[i] = unit + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// insideOf returns the provider in p.Inside rendered as element name.
+func insideOf(p *Provider, name string) *Provider {
+	for _, outer := range p.Inside {
+		if outer.Element == name {
+			return outer
+		}
+	}
+	return nil
+}
+
+func isExtra(p *Provider, name string) bool {
+	for _, extra := range p.Extras {
+		if extraName(extra) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// extraName returns the element name of an extra such as "<Devtools />".
+func extraName(extra string) string {
+	name := strings.TrimPrefix(extra, "<")
+	if i := strings.IndexFunc(name, func(r rune) bool { return r == ' ' || r == '/' || r == '>' }); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// significant drops whitespace-only text children.
+func significant(src []byte, children []*node) []*node {
+	var out []*node
+	for _, child := range children {
+		if !child.blank(src) {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// trimExtras drops p's extras from the end of children.
+func trimExtras(children []*node, p *Provider) []*node {
+	for len(children) > 0 {
+		last := children[len(children)-1]
+		if last.kind != elementNode || !isExtra(p, last.name) {
+			break
+		}
+		children = children[:len(children)-1]
+	}
+	return children
+}
+
+// indentUnit returns the indentation child adds over its enclosing element, defaulting to two spaces.
+func indentUnit(src []byte, child, enclosing *node) string {
+	childIndent, childLeading := lineIndent(src, child.start)
+	outerIndent, outerLeading := lineIndent(src, enclosing.start)
+	if childLeading && outerLeading && child != enclosing && strings.HasPrefix(childIndent, outerIndent) && len(childIndent) > len(outerIndent) {
+		return childIndent[len(outerIndent):]
+	}
+	return "  "
+}
+
+func splice(src []byte, start, end int, replacement string) []byte {
+	out := make([]byte, 0, len(src)-(end-start)+len(replacement))
+	out = append(out, src[:start]...)
+	out = append(out, replacement...)
+	return append(out, src[end:]...)
+}
+
+var setupNamePattern = regexp.MustCompile(`^(?:const|let|var)\s+([A-Za-z_$][\w$]*)`)
+
+// setupName returns the identifier a setup declaration introduces.
+func setupName(setup string) string {
+	if m := setupNamePattern.FindStringSubmatch(setup); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// declares reports whether the file declares name at the start of a line.
+func declares(src []byte, name string) bool {
+	if name == "" {
+		return false
+	}
+	return regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:const|let|var)\s+` + regexp.QuoteMeta(name) + `\b`).Match(src)
+}
+
+// insertSetup places the declaration after the last import, separated by blank
+// lines, and follows the imports' semicolon style.
+func insertSetup(src []byte, setup string) ([]byte, error) {
+	decls, err := parseImports(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(decls) > 0 && !decls[0].semicolon {
+		setup = strings.TrimSuffix(setup, ";")
+	}
+	if len(decls) > 0 {
+		at := decls[len(decls)-1].end
+		return splice(src, at, at, "\n\n"+setup), nil
+	}
+	return splice(src, 0, 0, setup+"\n\n"), nil
+}
+
+// removeDeclaration deletes the top-level declaration of name and the blank line it leaves behind.
+func removeDeclaration(src []byte, name string) []byte {
+	loc := regexp.MustCompile(`(?m)^(?:const|let|var)\s+` + regexp.QuoteMeta(name) + `\b`).FindIndex(src)
+	if loc == nil {
+		return src
+	}
+
+	end := loc[1]
+	for end < len(src) && src[end] != ';' && src[end] != '\n' {
+		if next := skipNonCode(src, end); next != end {
+			end = next
+			continue
+		}
+		if src[end] == '(' || src[end] == '{' || src[end] == '[' {
+			end = skipBalanced(src, end)
+			continue
+		}
+		end++
+	}
+	if end < len(src) && src[end] == ';' {
+		end++
+	}
+	if end < len(src) && src[end] == '\n' {
+		end++
+	}
+
+	start := loc[0]
+	// Collapse the blank line that separated the declaration from its neighbours.
+	if start >= 2 && src[start-1] == '\n' && src[start-2] == '\n' && end < len(src) && src[end] == '\n' {
+		end++
+	}
+	return splice(src, start, end, "")
+}
+
+// references reports whether name is used as an identifier outside comments,
+// strings and (when skipImports is set) import declarations.
+func references(src []byte, name string, skipImports bool) bool {
+	var skip [][2]int
+	if skipImports {
+		if decls, err := parseImports(src); err == nil {
+			for _, d := range decls {
+				skip = append(skip, [2]int{d.start, d.end})
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		if next := skipNonCode(src, i); next != i {
+			i = next
+			continue
+		}
+		skipped := false
+		for _, r := range skip {
+			if i >= r[0] && i < r[1] {
+				i, skipped = r[1], true
+				break
+			}
+		}
+		if skipped {
+			continue
+		}
+
+		if strings.HasPrefix(string(src[i:]), name) &&
+			(i == 0 || !isIdentByte(src[i-1])) &&
+			(i+len(name) >= len(src) || !isIdentByte(src[i+len(name)])) {
+			// A declaration of the name is not a use of it.
+			if !declares(src[lineStartOf(src, i):i+len(name)], name) {
+				return true
+			}
+		}
+		i++
+	}
+	return false
+}
+
+func lineStartOf(src []byte, i int) int {
+	for i > 0 && src[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// localName returns the name a specifier binds: "b" for "a as b", "X" for "type X".
+func localName(spec string) string {
+	fields := strings.Fields(spec)
+	return fields[len(fields)-1]
+}
+
+// addImport adds the names to an existing value import of the module, or a
+// new declaration placed in sorted order among the package imports (or the
+// relative imports, for a module inside the project).
+func addImport(src []byte, imp Import) ([]byte, error) {
+	decls, err := parseImports(src)
+	if err != nil {
+		return nil, err
+	}
+
+	have := map[string]bool{}
+	needDefault := imp.Default != ""
+	for _, d := range decls {
+		if d.module != imp.Module || d.typeOnly {
+			continue
+		}
+		for _, spec := range d.named {
+			have[localName(spec)] = true
+		}
+		if d.defaultName == imp.Default {
+			needDefault = false
+		}
+	}
+	var missing []string
+	for _, name := range imp.Names {
+		if !have[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 && !needDefault {
+		return src, nil
+	}
+
+	for _, d := range decls {
+		if needDefault {
+			break
+		}
+		if d.module != imp.Module || d.typeOnly || d.namespace != "" {
+			continue
+		}
+		if d.braces {
+			names := append(append([]string(nil), d.named...), missing...)
+			return splice(src, d.braceStart, d.braceEnd, "{ "+strings.Join(names, ", ")+" }"), nil
+		}
+		if d.defaultName != "" {
+			at := d.start + len("import")
+			at = strings.Index(string(src[at:d.end]), d.defaultName) + at + len(d.defaultName)
+			return splice(src, at, at, ", { "+strings.Join(missing, ", ")+" }"), nil
+		}
+	}
+
+	quote, semicolon := byte('\''), true
+	if len(decls) > 0 {
+		quote, semicolon = decls[0].quote, decls[0].semicolon
+	}
+	var clause []string
+	if needDefault {
+		clause = append(clause, imp.Default)
+	}
+	if len(missing) > 0 {
+		clause = append(clause, "{ "+strings.Join(missing, ", ")+" }")
+	}
+	line := "import " + strings.Join(clause, ", ") + " from " + string(quote) + imp.Module + string(quote)
+	if semicolon {
+		line += ";"
+	}
+
+	var packages, relatives []importDecl
+	for _, d := range decls {
+		if d.relative() {
+			relatives = append(relatives, d)
+		} else {
+			packages = append(packages, d)
+		}
+	}
+
+	// A relative module goes before the first relative import that sorts after
+	// it, otherwise after the last relative import, otherwise after the packages.
+	if strings.HasPrefix(imp.Module, ".") {
+		if i := sort.Search(len(relatives), func(i int) bool { return relatives[i].module > imp.Module }); i < len(relatives) {
+			return splice(src, relatives[i].start, relatives[i].start, line+"\n"), nil
+		}
+		if len(relatives) > 0 {
+			at := relatives[len(relatives)-1].end
+			return splice(src, at, at, "\n"+line), nil
+		}
+		if len(packages) > 0 {
+			at := packages[len(packages)-1].end
+			return splice(src, at, at, "\n\n"+line), nil
+		}
+		return splice(src, 0, 0, line+"\n"), nil
+	}
+
+	// Insert before the first package import that sorts after this module,
+	// otherwise before the first relative import, otherwise after the last import.
+	if i := sort.Search(len(packages), func(i int) bool { return packages[i].module > imp.Module }); i < len(packages) {
+		return splice(src, packages[i].start, packages[i].start, line+"\n"), nil
+	}
+	if len(packages) > 0 {
+		at := packages[len(packages)-1].end
+		return splice(src, at, at, "\n"+line), nil
+	}
+	for _, d := range decls {
+		if d.relative() {
+			return splice(src, d.start, d.start, line+"\n\n"), nil
+		}
+	}
+	return splice(src, 0, 0, line+"\n"), nil
+}
+
+// removeImport drops the default binding and names from value imports of the
+// module when the file no longer uses them, and deletes declarations left empty.
+func removeImport(src []byte, imp Import) ([]byte, error) {
+	if imp.Default != "" && !references(src, imp.Default, true) {
+		decls, err := parseImports(src)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range decls {
+			if d.module == imp.Module && !d.typeOnly && d.defaultName == imp.Default {
+				src = dropDefault(src, d)
+				break
+			}
+		}
+	}
+
+	for _, name := range imp.Names {
+		if references(src, name, true) {
+			continue
+		}
+
+		decls, err := parseImports(src)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range decls {
+			if d.module == imp.Module && d.braces {
+				if out, ok := dropSpecifier(src, d, name); ok {
+					src = out
+					break
+				}
+			}
+		}
+	}
+	return src, nil
+}
+
+// dropSpecifier removes name from the declaration, or the whole declaration
+// when nothing else is imported by it. ok is false when d does not import name.
+func dropSpecifier(src []byte, d importDecl, name string) (out []byte, ok bool) {
+	var kept []string
+	for _, spec := range d.named {
+		if localName(spec) != name {
+			kept = append(kept, spec)
+		}
+	}
+	if len(kept) == len(d.named) {
+		return src, false
+	}
+
+	switch {
+	case len(kept) > 0:
+		return splice(src, d.braceStart, d.braceEnd, "{ "+strings.Join(kept, ", ")+" }"), true
+	case d.defaultName != "" || d.namespace != "":
+		// Drop ", { }" after the default or namespace binding.
+		at := strings.LastIndex(string(src[d.start:d.braceStart]), ",") + d.start
+		return splice(src, at, d.braceEnd, ""), true
+	default:
+		return deleteImport(src, d), true
+	}
+}
+
+// dropDefault removes the default binding from d, or the whole declaration
+// when it imports nothing else.
+func dropDefault(src []byte, d importDecl) []byte {
+	if !d.braces && d.namespace == "" {
+		return deleteImport(src, d)
+	}
+	// Drop "Name, " before the named or namespace clause.
+	at := d.start + len("import")
+	at += strings.Index(string(src[at:d.end]), d.defaultName)
+	end := at + len(d.defaultName)
+	end = skipSpace(src, end)
+	if end < d.end && src[end] == ',' {
+		end = skipSpace(src, end+1)
+	}
+	return splice(src, at, end, "")
+}
+
+// deleteImport removes the declaration and its line break.
+func deleteImport(src []byte, d importDecl) []byte {
+	end := d.end
+	if end < len(src) && src[end] == '\n' {
+		end++
+	}
+	return splice(src, d.start, end, "")
+}
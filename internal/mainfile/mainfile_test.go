@@ -0,0 +1,188 @@
+package mainfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var query = &Provider{
+	Element: "QueryClientProvider",
+	Attrs:   "client={queryClient}",
+	Imports: []Import{
+		{Names: []string{"QueryClient", "QueryClientProvider"}, Module: "@tanstack/react-query"},
+		{Names: []string{"ReactQueryDevtools"}, Module: "@tanstack/react-query-devtools"},
+	},
+	Setup:  "const queryClient = new QueryClient();",
+	Extras: []string{"<ReactQueryDevtools initialIsOpen={false} />"},
+}
+
+const customMain = `import * as Sentry from "@sentry/react"
+import { StrictMode } from "react"
+import { createRoot } from "react-dom/client"
+import { BrowserRouter } from "react-router-dom"
+
+import App from "./App"
+import { AuthProvider } from "./auth"
+
+Sentry.init({ dsn: import.meta.env.VITE_SENTRY_DSN })
+
+// Keep the router outermost.
+createRoot(document.getElementById("root")!).render(
+	<StrictMode>
+		<BrowserRouter>
+			<AuthProvider>
+				<App />
+			</AuthProvider>
+		</BrowserRouter>
+	</StrictMode>,
+)
+`
+
+func TestInsertRemove_PreservesCustomCode(t *testing.T) {
+	got, err := Insert([]byte(customMain), query)
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	want := `import * as Sentry from "@sentry/react"
+import { QueryClient, QueryClientProvider } from "@tanstack/react-query"
+import { ReactQueryDevtools } from "@tanstack/react-query-devtools"
+import { StrictMode } from "react"
+import { createRoot } from "react-dom/client"
+import { BrowserRouter } from "react-router-dom"
+
+import App from "./App"
+import { AuthProvider } from "./auth"
+
+const queryClient = new QueryClient()
+
+Sentry.init({ dsn: import.meta.env.VITE_SENTRY_DSN })
+
+// Keep the router outermost.
+createRoot(document.getElementById("root")!).render(
+	<StrictMode>
+		<QueryClientProvider client={queryClient}>
+			<BrowserRouter>
+				<AuthProvider>
+					<App />
+				</AuthProvider>
+			</BrowserRouter>
+			<ReactQueryDevtools initialIsOpen={false} />
+		</QueryClientProvider>
+	</StrictMode>,
+)
+`
+	if string(got) != want {
+		t.Fatalf("Insert() got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if has, err := Has(got, query); err != nil || !has {
+		t.Fatalf("Has() = %v, %v; want true", has, err)
+	}
+	again, err := Insert(got, query)
+	if err != nil || string(again) != want {
+		t.Fatalf("second Insert() changed the file: %v", err)
+	}
+
+	back, err := Remove(got, query)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if string(back) != customMain {
+		t.Fatalf("Remove() got:\n%s\nwant:\n%s", back, customMain)
+	}
+}
+
+func TestRemove_KeepsImportsStillInUse(t *testing.T) {
+	src := `import { QueryClient, QueryClientProvider } from '@tanstack/react-query';
+import { ReactQueryDevtools } from '@tanstack/react-query-devtools';
+import ReactDOM from 'react-dom/client';
+
+import App from './App';
+
+const queryClient = new QueryClient();
+export const prefetch = () => queryClient.prefetchQuery({ queryKey: ['me'] });
+
+ReactDOM.createRoot(document.getElementById('root')!).render(
+  <QueryClientProvider client={queryClient}>
+    <App />
+    <ReactQueryDevtools initialIsOpen={false} />
+  </QueryClientProvider>
+);
+`
+	got, err := Remove([]byte(src), query)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	out := string(got)
+	if !strings.Contains(out, "import { QueryClient } from '@tanstack/react-query';") {
+		t.Errorf("QueryClient import dropped although queryClient is still used:\n%s", out)
+	}
+	if !strings.Contains(out, "const queryClient = new QueryClient();") {
+		t.Errorf("queryClient declaration dropped although it is still used:\n%s", out)
+	}
+	if strings.Contains(out, "ReactQueryDevtools") || strings.Contains(out, "QueryClientProvider") {
+		t.Errorf("provider or devtools left behind:\n%s", out)
+	}
+	if !strings.Contains(out, ".render(\n  <App />\n);") {
+		t.Errorf("App not unwrapped cleanly:\n%s", out)
+	}
+}
+
+func TestInsert_UnsupportedShapes(t *testing.T) {
+	cases := map[string]string{
+		"no render":   "export function mount() {}\n",
+		"two renders": "a.render(<App />)\nb.render(<Other />)\n",
+		"not jsx":     "root.render(app)\n",
+		"unclosed":    "root.render(<StrictMode><App /></Strict>)\n",
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Insert([]byte(src), query); !errors.Is(err, ErrUnsupported) {
+				t.Fatalf("Insert() error = %v, want ErrUnsupported", err)
+			}
+		})
+	}
+}
+
+func TestInsertBeforeRoot(t *testing.T) {
+	const start = "await start();\n\n"
+	cases := map[string]struct{ src, want string }{
+		"element lookup": {
+			src:  "import App from './App';\n\nconst el = document.getElementById('root');\ncreateRoot(el!).render(<App />);\n",
+			want: "import App from './App';\n\n" + start + "const el = document.getElementById('root');\ncreateRoot(el!).render(<App />);\n",
+		},
+		"inline root": {
+			src:  "Sentry.init({ dsn: 'x' });\n\nReactDOM.createRoot(\n  document.getElementById('root')!,\n).render(<App />);\n",
+			want: "Sentry.init({ dsn: 'x' });\n\n" + start + "ReactDOM.createRoot(\n  document.getElementById('root')!,\n).render(<App />);\n",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := InsertBeforeRoot([]byte(c.src), start)
+			if err != nil {
+				t.Fatalf("InsertBeforeRoot() error = %v", err)
+			}
+			if string(got) != c.want {
+				t.Fatalf("InsertBeforeRoot() =\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestInsertBeforeRoot_UnsupportedShapes(t *testing.T) {
+	cases := map[string]string{
+		"no root":  "console.log('createRoot(');\n",
+		"function": "function mount() {\n  createRoot(document.getElementById('root')!).render(<App />);\n}\n\nmount();\n",
+		"callback": "document.addEventListener('DOMContentLoaded', () =>\n  createRoot(document.getElementById('root')!).render(<App />),\n);\n",
+		"block":    "if (el) {\n  createRoot(el).render(<App />);\n}\n",
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := InsertBeforeRoot([]byte(src), "await start();\n"); !errors.Is(err, ErrUnsupported) {
+				t.Fatalf("InsertBeforeRoot() error = %v, want ErrUnsupported", err)
+			}
+		})
+	}
+}
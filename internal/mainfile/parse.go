@@ -0,0 +1,418 @@
+package mainfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// importDecl is one top-level import declaration.
+type importDecl struct {
+	start, end  int // byte range, including a trailing semicolon
+	module      string
+	quote       byte
+	semicolon   bool
+	typeOnly    bool
+	defaultName string
+	namespace   string
+	named       []string // specifiers as written, e.g. "QueryClient" or "foo as bar"
+	braces      bool     // the declaration has a { ... } clause
+	braceStart  int      // offset of '{' when braces is set
+	braceEnd    int      // offset just past '}' when braces is set
+}
+
+// relative reports whether the import points into the project.
+func (d importDecl) relative() bool {
+	return strings.HasPrefix(d.module, ".")
+}
+
+// node is a JSX element, text run or {expression} child.
+type node struct {
+	kind       nodeKind
+	name       string // element name; empty for fragments
+	start, end int
+	children   []*node
+}
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	exprNode
+	elementNode
+)
+
+// blank reports whether the node is whitespace-only text.
+func (n *node) blank(src []byte) bool {
+	return n.kind == textNode && strings.TrimSpace(string(src[n.start:n.end])) == ""
+}
+
+// skipNonCode returns the offset after a comment or string literal starting at i, or i.
+func skipNonCode(src []byte, i int) int {
+	if i >= len(src) {
+		return i
+	}
+
+	switch c := src[i]; {
+	case c == '/' && i+1 < len(src) && src[i+1] == '/':
+		for i < len(src) && src[i] != '\n' {
+			i++
+		}
+		return i
+	case c == '/' && i+1 < len(src) && src[i+1] == '*':
+		end := strings.Index(string(src[i+2:]), "*/")
+		if end < 0 {
+			return len(src)
+		}
+		return i + 2 + end + 2
+	case c == '\'' || c == '"':
+		for i++; i < len(src) && src[i] != c && src[i] != '\n'; i++ {
+			if src[i] == '\\' {
+				i++
+			}
+		}
+		return i + 1
+	case c == '`':
+		for i++; i < len(src) && src[i] != '`'; i++ {
+			switch {
+			case src[i] == '\\':
+				i++
+			case src[i] == '$' && i+1 < len(src) && src[i+1] == '{':
+				i = skipBalanced(src, i+1) - 1
+			}
+		}
+		return i + 1
+	}
+	return i
+}
+
+// skipBalanced returns the offset just past the bracket that closes the one at i.
+func skipBalanced(src []byte, i int) int {
+	depth := 0
+	for i < len(src) {
+		if next := skipNonCode(src, i); next != i {
+			i = next
+			continue
+		}
+		switch src[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return len(src)
+}
+
+// codeIndex returns the offset of the first occurrence of needle outside
+// comments and string literals, starting at from, or -1.
+func codeIndex(src []byte, needle string, from int) int {
+	for i := from; i < len(src); {
+		if next := skipNonCode(src, i); next != i {
+			i = next
+			continue
+		}
+		if strings.HasPrefix(string(src[i:]), needle) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// parseImports finds the top-level import declarations.
+func parseImports(src []byte) ([]importDecl, error) {
+	var decls []importDecl
+	depth := 0
+	lineStart := true
+
+	for i := 0; i < len(src); {
+		if next := skipNonCode(src, i); next != i {
+			i = next
+			continue
+		}
+
+		c := src[i]
+		if depth == 0 && lineStart && strings.HasPrefix(string(src[i:]), "import") && i+6 < len(src) && isImportFollower(src[i+6]) {
+			d, err := parseImport(src, i)
+			if err != nil {
+				return nil, err
+			}
+			decls = append(decls, d)
+			i = d.end
+			continue
+		}
+
+		switch {
+		case c == '{' || c == '(' || c == '[':
+			depth++
+		case c == '}' || c == ')' || c == ']':
+			depth--
+		}
+		if c == '\n' {
+			lineStart = true
+		} else if c != ' ' && c != '\t' && c != '\r' {
+			lineStart = false
+		}
+		i++
+	}
+	return decls, nil
+}
+
+func isImportFollower(c byte) bool {
+	return c == ' ' || c == '\t' || c == '{' || c == '*' || c == '\'' || c == '"'
+}
+
+// parseImport reads the import declaration starting at the "import" keyword at start.
+func parseImport(src []byte, start int) (importDecl, error) {
+	d := importDecl{start: start}
+	i := start + len("import")
+
+	// Everything up to the module string is the import clause.
+	for i < len(src) && src[i] != '\'' && src[i] != '"' {
+		if src[i] == '{' {
+			d.braces, d.braceStart = true, i
+			end := skipBalanced(src, i)
+			d.braceEnd = end
+			for _, spec := range strings.Split(string(src[i+1:end-1]), ",") {
+				if spec = strings.Join(strings.Fields(spec), " "); spec != "" {
+					d.named = append(d.named, spec)
+				}
+			}
+			i = end
+			continue
+		}
+		if src[i] == ';' {
+			return d, unsupported("malformed import at offset %d", start)
+		}
+		i++
+	}
+	if i >= len(src) {
+		return d, unsupported("unterminated import at offset %d", start)
+	}
+
+	clause := string(src[start+len("import") : i])
+	if d.braces {
+		clause = string(src[start+len("import"):d.braceStart]) + string(src[d.braceEnd:i])
+	}
+	fields := strings.Fields(strings.ReplaceAll(clause, ",", " "))
+	if len(fields) > 0 && fields[0] == "type" {
+		d.typeOnly = true
+		fields = fields[1:]
+	}
+	for j := 0; j < len(fields); j++ {
+		switch {
+		case fields[j] == "from":
+		case fields[j] == "*" && j+2 < len(fields) && fields[j+1] == "as":
+			d.namespace = fields[j+2]
+			j += 2
+		default:
+			d.defaultName = fields[j]
+		}
+	}
+
+	d.quote = src[i]
+	end := skipNonCode(src, i)
+	d.module = string(src[i+1 : end-1])
+	d.end = end
+	if d.end < len(src) && src[d.end] == ';' {
+		d.semicolon = true
+		d.end++
+	}
+	return d, nil
+}
+
+// findRender locates the single argument of the root render(...) call.
+func findRender(src []byte) (*node, error) {
+	at := codeIndex(src, ".render(", 0)
+	if at < 0 {
+		return nil, unsupported("no root.render(...) call found")
+	}
+	if codeIndex(src, ".render(", at+1) >= 0 {
+		return nil, unsupported("more than one render(...) call")
+	}
+
+	i := skipSpace(src, at+len(".render("))
+	if i >= len(src) || src[i] != '<' {
+		return nil, unsupported("render(...) does not receive a JSX element")
+	}
+
+	root, err := parseElement(src, i)
+	if err != nil {
+		return nil, err
+	}
+
+	j := skipSpace(src, root.end)
+	if j < len(src) && src[j] == ',' {
+		j = skipSpace(src, j+1)
+	}
+	if j >= len(src) || src[j] != ')' {
+		return nil, unsupported("unexpected content after the rendered element")
+	}
+	return root, nil
+}
+
+// rootAnchors are the calls that look up, create or render the React root.
+var rootAnchors = []string{"getElementById(", "createRoot(", ".render("}
+
+// rootStatement returns the offset of the top-level statement holding the
+// first call in rootAnchors. Statements start at column 0 outside brackets; a
+// call inside a block, function or callback is unsupported.
+func rootStatement(src []byte) (int, error) {
+	var open []byte
+	stmt := 0
+	for i := 0; i < len(src); {
+		if next := skipNonCode(src, i); next != i {
+			i = next
+			continue
+		}
+		for _, anchor := range rootAnchors {
+			if !strings.HasPrefix(string(src[i:]), anchor) {
+				continue
+			}
+			if strings.IndexByte(string(open), '{') >= 0 || codeIndex(src[:i], "=>", stmt) >= 0 || codeIndex(src[:i], "function", stmt) >= 0 {
+				return 0, unsupported("the root is created inside a function or block")
+			}
+			return stmt, nil
+		}
+
+		switch c := src[i]; c {
+		case '(', '[', '{':
+			open = append(open, c)
+		case ')', ']', '}':
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case '\n':
+			if len(open) == 0 && i+1 < len(src) && !strings.ContainsRune(" \t\r\n", rune(src[i+1])) {
+				stmt = i + 1
+			}
+		}
+		i++
+	}
+	return 0, unsupported("no root.render(...) call found")
+}
+
+// parseElement parses the JSX element or fragment starting at '<'.
+func parseElement(src []byte, start int) (*node, error) {
+	n := &node{kind: elementNode, start: start}
+	i := start + 1
+
+	nameEnd := i
+	for nameEnd < len(src) && isNameByte(src[nameEnd]) {
+		nameEnd++
+	}
+	n.name = string(src[i:nameEnd])
+	i = nameEnd
+
+	// Attributes: skip strings and {expressions} until the tag ends.
+	for {
+		if i >= len(src) {
+			return nil, unsupported("unterminated <%s> tag", n.name)
+		}
+		if next := skipNonCode(src, i); next != i && (src[i] == '\'' || src[i] == '"') {
+			i = next
+			continue
+		}
+		switch {
+		case src[i] == '{':
+			i = skipBalanced(src, i)
+			continue
+		case src[i] == '/' && i+1 < len(src) && src[i+1] == '>':
+			n.end = i + 2
+			return n, nil
+		case src[i] == '>':
+			i++
+		default:
+			i++
+			continue
+		}
+		break
+	}
+
+	// Children until the matching closing tag.
+	for {
+		if i >= len(src) {
+			return nil, unsupported("missing closing tag for <%s>", n.name)
+		}
+		switch {
+		case strings.HasPrefix(string(src[i:]), "</"):
+			closeEnd := strings.IndexByte(string(src[i:]), '>')
+			if closeEnd < 0 {
+				return nil, unsupported("unterminated closing tag for <%s>", n.name)
+			}
+			if name := strings.TrimSpace(string(src[i+2 : i+closeEnd])); name != n.name {
+				return nil, unsupported("<%s> is closed by </%s>", n.name, name)
+			}
+			n.end = i + closeEnd + 1
+			return n, nil
+		case src[i] == '<':
+			child, err := parseElement(src, i)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+			i = child.end
+		case src[i] == '{':
+			end := skipBalanced(src, i)
+			n.children = append(n.children, &node{kind: exprNode, start: i, end: end})
+			i = end
+		default:
+			end := i
+			for end < len(src) && src[end] != '<' && src[end] != '{' {
+				end++
+			}
+			n.children = append(n.children, &node{kind: textNode, start: i, end: end})
+			i = end
+		}
+	}
+}
+
+func isNameByte(c byte) bool {
+	return c == '.' || c == '_' || c == '-' || c == ':' || c == '$' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func skipSpace(src []byte, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\n' || src[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// find returns the first element named name in the tree, depth first.
+func (n *node) find(name string) *node {
+	if n.kind != elementNode {
+		return nil
+	}
+	if n.name == name {
+		return n
+	}
+	for _, child := range n.children {
+		if found := child.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// lineIndent returns the indentation of the line containing offset i and
+// whether only that indentation precedes i on its line.
+func lineIndent(src []byte, i int) (indent string, leading bool) {
+	start := i
+	for start > 0 && src[start-1] != '\n' {
+		start--
+	}
+	end := start
+	for end < i && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end]), end == i
+}
+
+func unsupported(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, fmt.Sprintf(format, args...))
+}
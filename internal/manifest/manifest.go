@@ -0,0 +1,122 @@
+// Package manifest reads and writes .sparky.json, the record of what go-sparky scaffolded.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"slices"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+// Filename is the manifest path relative to the project root.
+const Filename = ".sparky.json"
+
+// Manifest records the resolved plan, installed packages and generated file hashes of a project.
+type Manifest struct {
+	Version  string            `json:"version"`  // go-sparky version that last updated the manifest
+	Plan     plan.Plan         `json:"plan"`     // scaffold plan, kept in sync by add/remove
+	Features []string          `json:"features"` // installed go-sparky stacks, including add-only ones
+	Packages map[string]string `json:"packages"` // package name -> installed version
+	Files    map[string]string `json:"files"`    // generated file path -> sha256 of the content we wrote
+}
+
+// New returns an empty manifest for the plan.
+func New(p plan.Plan) *Manifest {
+	return &Manifest{
+		Plan:     p,
+		Features: []string{},
+		Packages: map[string]string{},
+		Files:    map[string]string{},
+	}
+}
+
+// Load reads the manifest from the current directory.
+// It returns an error satisfying os.IsNotExist when the project has no manifest.
+func Load() (*Manifest, error) {
+	data, err := fsys.ReadFile(Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m := New(plan.Plan{})
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	if m.Packages == nil {
+		m.Packages = map[string]string{}
+	}
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	return m, nil
+}
+
+// Save writes the manifest to the current directory.
+func (m *Manifest) Save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return fsys.WriteFile(Filename, append(data, '\n'), 0o644)
+}
+
+// HasFeature reports whether the named stack is recorded as installed.
+func (m *Manifest) HasFeature(name string) bool {
+	return slices.Contains(m.Features, name)
+}
+
+// SetFeature records the named stack as installed or removed.
+func (m *Manifest) SetFeature(name string, installed bool) {
+	m.Features = slices.DeleteFunc(m.Features, func(f string) bool { return f == name })
+	if installed {
+		m.Features = append(m.Features, name)
+	}
+}
+
+// RecordFile stores the hash of the file's current content.
+// Missing files are forgotten instead.
+func (m *Manifest) RecordFile(path string) error {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			delete(m.Files, path)
+			return nil
+		}
+		return err
+	}
+
+	m.Files[path] = Hash(data)
+	return nil
+}
+
+// ForgetFile stops tracking a generated file.
+func (m *Manifest) ForgetFile(path string) {
+	delete(m.Files, path)
+}
+
+// Pristine reports whether path is a tracked generated file whose content is unchanged.
+func (m *Manifest) Pristine(path string) bool {
+	want, ok := m.Files[path]
+	if !ok {
+		return false
+	}
+
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return Hash(data) == want
+}
+
+// Hash returns the hex-encoded sha256 of data.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
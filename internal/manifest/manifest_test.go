@@ -0,0 +1,58 @@
+package manifest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+func TestManifest_RoundTripAndPristine(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if err := os.WriteFile("vercel.json", []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write vercel.json: %v", err)
+	}
+
+	m := New(plan.Plan{Name: "demo", Bundler: plan.BundlerVite, Vercel: true})
+	m.SetFeature("vercel", true)
+	m.Packages["react"] = "19.0.0"
+	if err := m.RecordFile("vercel.json"); err != nil {
+		t.Fatalf("RecordFile: %v", err)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Plan.Name != "demo" || !loaded.Plan.Vercel || !loaded.HasFeature("vercel") {
+		t.Fatalf("plan/features not restored: %+v", loaded)
+	}
+	if loaded.Packages["react"] != "19.0.0" {
+		t.Fatalf("packages not restored: %+v", loaded.Packages)
+	}
+	if !loaded.Pristine("vercel.json") {
+		t.Fatalf("expected vercel.json to be pristine")
+	}
+
+	if err := os.WriteFile("vercel.json", []byte(`{"edited": true}`), 0o644); err != nil {
+		t.Fatalf("edit vercel.json: %v", err)
+	}
+	if loaded.Pristine("vercel.json") {
+		t.Fatalf("expected edited vercel.json to be reported as modified")
+	}
+	if loaded.Pristine("netlify.toml") {
+		t.Fatalf("untracked files are never pristine")
+	}
+}
+
+func TestLoad_MissingManifest(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if _, err := Load(); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,433 @@
+// Package pkgjson edits package.json while keeping its key order, indentation,
+// line endings and the formatting of the values it does not change.
+package pkgjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// Dependency sections of package.json.
+const (
+	Dependencies         = "dependencies"
+	DevDependencies      = "devDependencies"
+	PeerDependencies     = "peerDependencies"
+	OptionalDependencies = "optionalDependencies"
+)
+
+// sections is the order Dependency searches the dependency sections in.
+var sections = []string{Dependencies, DevDependencies, PeerDependencies, OptionalDependencies}
+
+// Declared is a dependency as package.json declares it.
+type Declared struct {
+	Section string // e.g. Dependencies or DevDependencies
+	Range   string // version range as written, e.g. "^5.0.0"
+}
+
+// File is a parsed package.json.
+type File struct {
+	root    *object
+	indent  string
+	newline bool
+	crlf    bool
+}
+
+// New returns an empty package.json.
+func New() *File {
+	return &File{root: newObject(), indent: "  ", newline: true}
+}
+
+// Load reads and parses package.json at path.
+func Load(path string) (*File, error) {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse parses package.json content.
+func Parse(data []byte) (*File, error) {
+	root, err := parseObject(data)
+	if err != nil {
+		return nil, err
+	}
+	// A one-line file has no layout to keep, so it is indented like a new one.
+	if !bytes.Contains(bytes.TrimSpace(data), []byte("\n")) {
+		for _, key := range root.keys {
+			root.edited[key] = true
+		}
+	}
+
+	return &File{
+		root:    root,
+		indent:  detectIndent(data),
+		newline: bytes.HasSuffix(data, []byte("\n")),
+		crlf:    bytes.Contains(data, []byte("\r\n")),
+	}, nil
+}
+
+// Save writes the file to path.
+func (f *File) Save(path string) error {
+	data, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	return fsys.WriteFile(path, data, 0o644)
+}
+
+// Bytes renders the file with its original indentation and line endings.
+// Top-level values that were not changed are written back as they were read.
+func (f *File) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if err := f.root.render(&b, f.indent); err != nil {
+		return nil, err
+	}
+	if f.newline {
+		b.WriteByte('\n')
+	}
+	if f.crlf {
+		return bytes.ReplaceAll(b.Bytes(), []byte("\n"), []byte("\r\n")), nil
+	}
+	return b.Bytes(), nil
+}
+
+// SetDependency declares name at version in dependencies or devDependencies,
+// moving it out of the other section if it was declared there.
+func (f *File) SetDependency(name, version string, dev bool) error {
+	section, other := Dependencies, DevDependencies
+	if dev {
+		section, other = other, section
+	}
+
+	if deps, err := f.section(other, false); err != nil {
+		return err
+	} else if deps != nil {
+		deps.delete(name)
+		if err := f.root.setObject(other, deps); err != nil {
+			return err
+		}
+	}
+
+	deps, err := f.section(section, true)
+	if err != nil {
+		return err
+	}
+	if err := deps.setString(name, version); err != nil {
+		return err
+	}
+	deps.sortIfSorted(name)
+	return f.root.setObject(section, deps)
+}
+
+// Dependency reports where name is declared and at which range. Only the
+// dependency sections are searched, so scripts or other fields that mention
+// the name do not count.
+func (f *File) Dependency(name string) (Declared, bool) {
+	for _, section := range sections {
+		deps, err := f.section(section, false)
+		if err != nil || deps == nil {
+			continue
+		}
+		raw, ok := deps.values[name]
+		if !ok {
+			continue
+		}
+		var version string
+		if err := json.Unmarshal(raw, &version); err != nil {
+			continue
+		}
+		return Declared{Section: section, Range: version}, true
+	}
+	return Declared{}, false
+}
+
+// DependencyNames returns the names declared in section, in file order.
+func (f *File) DependencyNames(section string) []string {
+	deps, err := f.section(section, false)
+	if err != nil || deps == nil {
+		return nil
+	}
+	return append([]string(nil), deps.keys...)
+}
+
+// HasDependency reports whether any dependency section declares name.
+func (f *File) HasDependency(name string) bool {
+	_, ok := f.Dependency(name)
+	return ok
+}
+
+// Script returns the command of the named script.
+func (f *File) Script(name string) (string, bool) {
+	scripts, err := f.section("scripts", false)
+	if err != nil || scripts == nil {
+		return "", false
+	}
+	var command string
+	if err := json.Unmarshal(scripts.values[name], &command); err != nil {
+		return "", false
+	}
+	return command, true
+}
+
+// SetScript sets the named script, adding it after the existing scripts
+// when it is new.
+func (f *File) SetScript(name, command string) error {
+	scripts, err := f.section("scripts", true)
+	if err != nil {
+		return err
+	}
+	if err := scripts.setString(name, command); err != nil {
+		return err
+	}
+	return f.root.setObject("scripts", scripts)
+}
+
+// RemoveScript deletes the named script if it exists.
+func (f *File) RemoveScript(name string) error {
+	scripts, err := f.section("scripts", false)
+	if err != nil || scripts == nil {
+		return err
+	}
+	scripts.delete(name)
+	return f.root.setObject("scripts", scripts)
+}
+
+// Field decodes the top-level field key into v and reports whether it exists.
+func (f *File) Field(key string, v any) (bool, error) {
+	raw, ok := f.root.values[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("%s: %w", key, err)
+	}
+	return true, nil
+}
+
+// SetField sets the top-level field key to v, keeping its position when it
+// already exists and appending it otherwise.
+func (f *File) SetField(key string, v any) error {
+	raw, err := marshalValue(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	f.root.set(key, raw)
+	return nil
+}
+
+// RemoveField deletes the top-level field key if it exists.
+func (f *File) RemoveField(key string) {
+	f.root.delete(key)
+}
+
+// section returns an object-valued field such as a dependency section, or nil when it is missing and create is false.
+func (f *File) section(name string, create bool) (*object, error) {
+	raw, ok := f.root.values[name]
+	if !ok {
+		if !create {
+			return nil, nil
+		}
+		return newObject(), nil
+	}
+
+	obj, err := parseObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// object is a JSON object that remembers the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+	edited map[string]bool // values set since parsing
+}
+
+func newObject() *object {
+	return &object{values: map[string]json.RawMessage{}, edited: map[string]bool{}}
+}
+
+func parseObject(data []byte) (*object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	obj := newObject()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if _, dup := obj.values[key]; !dup {
+			obj.keys = append(obj.keys, key)
+		}
+		obj.values[key] = value
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *object) set(key string, value json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+	o.edited[key] = true
+}
+
+func (o *object) setString(key, value string) error {
+	raw, err := marshalString(value)
+	if err != nil {
+		return err
+	}
+	o.set(key, raw)
+	return nil
+}
+
+func (o *object) setObject(key string, value *object) error {
+	raw, err := value.marshal()
+	if err != nil {
+		return err
+	}
+	o.set(key, raw)
+	return nil
+}
+
+func (o *object) delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	delete(o.edited, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// sortIfSorted keeps alphabetical sections alphabetical after adding key,
+// the way npm writes them; hand-ordered sections keep their order.
+func (o *object) sortIfSorted(key string) {
+	rest := make([]string, 0, len(o.keys))
+	for _, k := range o.keys {
+		if k != key {
+			rest = append(rest, k)
+		}
+	}
+	if !sort.StringsAreSorted(rest) {
+		return
+	}
+	sort.Strings(o.keys)
+}
+
+func (o *object) marshal() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := marshalString(key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		if err := json.Compact(&b, o.values[key]); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// render writes the object with one key per line. Values read from the file
+// are copied byte for byte, so inline arrays and objects stay inline; edited
+// values are indented to match.
+func (o *object) render(b *bytes.Buffer, indent string) error {
+	if len(o.keys) == 0 {
+		b.WriteString("{}")
+		return nil
+	}
+
+	b.WriteString("{\n")
+	for i, key := range o.keys {
+		k, err := marshalString(key)
+		if err != nil {
+			return err
+		}
+		b.WriteString(indent)
+		b.Write(k)
+		b.WriteString(": ")
+		if o.edited[key] {
+			if err := json.Indent(b, o.values[key], indent, indent); err != nil {
+				return err
+			}
+		} else {
+			// Bytes restores CRLF line endings across the whole file.
+			b.Write(bytes.ReplaceAll(o.values[key], []byte("\r\n"), []byte("\n")))
+		}
+		if i < len(o.keys)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+func marshalString(s string) (json.RawMessage, error) {
+	return marshalValue(s)
+}
+
+// marshalValue encodes v without escaping <, > and &, which npm leaves readable.
+func marshalValue(v any) (json.RawMessage, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// detectIndent returns the indentation of the first indented line, defaulting to two spaces.
+func detectIndent(data []byte) string {
+	for _, line := range bytes.Split(data, []byte("\n"))[1:] {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) == 0 || len(trimmed) == len(line) {
+			continue
+		}
+		return string(line[:len(line)-len(trimmed)])
+	}
+	return "  "
+}
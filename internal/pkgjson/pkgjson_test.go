@@ -0,0 +1,209 @@
+package pkgjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetDependency_PreservesOrderAndIndent(t *testing.T) {
+	src := `{
+    "name": "app",
+    "scripts": {
+        "dev": "vite"
+    },
+    "dependencies": {
+        "react": "^19.0.0"
+    },
+    "devDependencies": {
+        "vite": "^7.0.0",
+        "zustand": "^5.0.0"
+    }
+}
+`
+	f, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetDependency("@tanstack/react-query", "^5.0.0", false); err != nil {
+		t.Fatal(err)
+	}
+	// zustand moves from devDependencies to dependencies.
+	if err := f.SetDependency("zustand", "^5.0.1", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetDependency("eslint", "^9.0.0", true); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+    "name": "app",
+    "scripts": {
+        "dev": "vite"
+    },
+    "dependencies": {
+        "@tanstack/react-query": "^5.0.0",
+        "react": "^19.0.0",
+        "zustand": "^5.0.1"
+    },
+    "devDependencies": {
+        "eslint": "^9.0.0",
+        "vite": "^7.0.0"
+    }
+}
+`
+	if string(got) != want {
+		t.Fatalf("Bytes() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSetDependency_CreatesSection(t *testing.T) {
+	f, err := Parse([]byte(`{"name":"app","scripts":{"test":"a && b > c"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetDependency("husky", "^9.0.0", true); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "name": "app",
+  "scripts": {
+    "test": "a && b > c"
+  },
+  "devDependencies": {
+    "husky": "^9.0.0"
+  }
+}`
+	if string(got) != want {
+		t.Fatalf("Bytes() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBytes_KeepsUntouchedValuesAndCRLF(t *testing.T) {
+	src := "{\r\n  \"name\": \"app\",\r\n  \"files\": [\"dist\"],\r\n  \"engines\": { \"node\": \">=20\" },\r\n  \"dependencies\": {\r\n    \"react\": \"^19.0.0\"\r\n  }\r\n}\r\n"
+	f, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetDependency("zustand", "^5.0.2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.ReplaceAll(`{
+  "name": "app",
+  "files": ["dist"],
+  "engines": { "node": ">=20" },
+  "dependencies": {
+    "react": "^19.0.0",
+    "zustand": "^5.0.2"
+  }
+}
+`, "\n", "\r\n")
+	if string(got) != want {
+		t.Fatalf("Bytes() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDependency_OnlySearchesDependencySections(t *testing.T) {
+	f, err := Parse([]byte(`{
+  "name": "app",
+  "description": "uses zustand and @mantine/core",
+  "scripts": {"tailwindcss-watch": "tailwindcss -w"},
+  "dependencies": {"react": "^19.0.0"},
+  "devDependencies": {"vite": "~7.1.0"}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := f.Dependency("vite"); !ok || got != (Declared{Section: DevDependencies, Range: "~7.1.0"}) {
+		t.Errorf("Dependency(vite) = %+v, %v", got, ok)
+	}
+	if got, ok := f.Dependency("react"); !ok || got.Section != Dependencies {
+		t.Errorf("Dependency(react) = %+v, %v", got, ok)
+	}
+	for _, name := range []string{"tailwindcss", "tailwindcss-watch", "zustand", "@mantine/core"} {
+		if f.HasDependency(name) {
+			t.Errorf("HasDependency(%q) = true for a name only mentioned outside dependencies", name)
+		}
+	}
+	if got := f.DependencyNames(DevDependencies); len(got) != 1 || got[0] != "vite" {
+		t.Errorf("DependencyNames(devDependencies) = %v, want [vite]", got)
+	}
+	if got := f.DependencyNames(PeerDependencies); got != nil {
+		t.Errorf("DependencyNames(peerDependencies) = %v, want none", got)
+	}
+}
+
+func TestSetScriptAndField(t *testing.T) {
+	f, err := Parse([]byte(`{
+  "name": "app",
+  "scripts": {
+    "dev": "vite"
+  }
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetScript("lint", "eslint ."); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetScript("dev", "vite --host"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetField("engines", map[string]string{"node": ">=20.19"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetField("packageManager", "pnpm@10.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetField("msw", map[string][]string{"workerDirectory": {"public"}}); err != nil {
+		t.Fatal(err)
+	}
+	f.RemoveField("msw")
+
+	if got, ok := f.Script("lint"); !ok || got != "eslint ." {
+		t.Errorf("Script(lint) = %q, %v", got, ok)
+	}
+	var engines map[string]string
+	if ok, err := f.Field("engines", &engines); !ok || err != nil || engines["node"] != ">=20.19" {
+		t.Errorf("Field(engines) = %v, %v, %v", engines, ok, err)
+	}
+
+	got, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "name": "app",
+  "scripts": {
+    "dev": "vite --host",
+    "lint": "eslint ."
+  },
+  "engines": {
+    "node": ">=20.19"
+  },
+  "packageManager": "pnpm@10.0.0"
+}
+`
+	if string(got) != want {
+		t.Fatalf("Bytes() =\n%s\nwant\n%s", got, want)
+	}
+}
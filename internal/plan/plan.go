@@ -1,5 +1,7 @@
 package plan
 
+import "github.com/hotslug/go-sparky/internal/pm"
+
 // BundlerType tracks the chosen frontend bundler.
 type BundlerType string
 
@@ -10,21 +12,39 @@ const (
 
 // Plan captures the requested project configuration derived from CLI flags.
 type Plan struct {
-	Name       string
-	Bundler    BundlerType
-	Mantine    bool
-	Tailwind   bool
-	ReactQuery bool
-	Zustand    bool
-	Eslint     bool
-	Prettier   bool
-	Husky      bool
-	StyledApp  bool
-	Framer     bool
-	Docker     bool
-	Vercel     bool
-	Netlify    bool
-	Storybook  bool
+	Name       string      `json:"name"`
+	Bundler    BundlerType `json:"bundler"`
+	PM         pm.Manager  `json:"packageManager,omitempty"`
+	Stack      string      `json:"stack,omitempty"` // version profile: pinned, latest or a profile file
+	Mantine    bool        `json:"mantine"`
+	Tailwind   bool        `json:"tailwind"`
+	ReactQuery bool        `json:"reactQuery"`
+	Zustand    bool        `json:"zustand"`
+	Eslint     bool        `json:"eslint"`
+	Prettier   bool        `json:"prettier"`
+	Husky      bool        `json:"husky"`
+	StyledApp  bool        `json:"styledApp"`
+	Framer     bool        `json:"framerMotion"`
+	Docker     bool        `json:"docker"`
+	Vercel     bool        `json:"vercel"`
+	Netlify    bool        `json:"netlify"`
+	Storybook  bool        `json:"storybook"`
+	Bulma      bool        `json:"bulma"`
+	Shadcn     bool        `json:"shadcn"`
+
+	ReactRouter    bool `json:"reactRouter,omitempty"`
+	TanStackRouter bool `json:"tanstackRouter,omitempty"`
+	Vitest         bool `json:"vitest,omitempty"`
+	BunTest        bool `json:"bunTest,omitempty"`
+	Playwright     bool `json:"playwright,omitempty"`
+	MSW            bool `json:"msw,omitempty"`
+
+	// Switches for a single scaffold run; they are not recorded in .sparky.json.
+	Dir       string `json:"-"` // target directory as given on the command line; Name is its base name
+	NoInstall bool   `json:"-"` // write package.json without installing dependencies
+	NoGit     bool   `json:"-"` // skip git init and the initial commit
+	NoPrompt  bool   `json:"-"` // run interactive tools with their defaults
+	Browsers  bool   `json:"-"` // download the Playwright browser after installing dependencies
 }
 
 // IsVite returns true when the plan targets Vite.
@@ -33,10 +53,16 @@ func (p Plan) IsVite() bool { return p.Bundler == BundlerVite }
 // IsBun returns true when the plan targets Bun.
 func (p Plan) IsBun() bool { return p.Bundler == BundlerBun }
 
-// PackageManager returns the package manager for the bundler.
-func (p Plan) PackageManager() string {
+// HasRouter reports whether the plan adds a router.
+func (p Plan) HasRouter() bool { return p.ReactRouter || p.TanStackRouter }
+
+// PackageManager returns the chosen package manager, defaulting to pnpm for Vite and bun for Bun.
+func (p Plan) PackageManager() pm.Manager {
+	if p.PM != "" {
+		return p.PM
+	}
 	if p.IsBun() {
-		return "bun"
+		return pm.Bun
 	}
-	return "pnpm"
+	return pm.PNPM
 }
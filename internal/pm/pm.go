@@ -0,0 +1,232 @@
+// Package pm describes the command dialects of the supported package managers
+// and detects which one an existing project uses.
+package pm
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// Manager identifies a package manager. Yarn is split by major version because
+// Berry (2+) changed its CLI and lockfile policy.
+type Manager string
+
+const (
+	NPM       Manager = "npm"
+	Yarn      Manager = "yarn"
+	YarnBerry Manager = "yarn-berry"
+	PNPM      Manager = "pnpm"
+	Bun       Manager = "bun"
+)
+
+// Names lists the values accepted by --pm.
+var Names = []string{"npm", "yarn", "pnpm", "bun"}
+
+// YarnVersion reports the version of the yarn on PATH, e.g. "4.9.2".
+var YarnVersion = func() (string, error) {
+	out, err := exec.Command("yarn", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// yarnMajor reports the major version of the yarn on PATH.
+var yarnMajor = func() (int, error) {
+	version, err := YarnVersion()
+	if err != nil {
+		return 0, err
+	}
+	major, _, _ := strings.Cut(version, ".")
+	return strconv.Atoi(major)
+}
+
+// Parse resolves a --pm value. "yarn" becomes YarnBerry when the installed yarn is 2 or newer.
+func Parse(name string) (Manager, error) {
+	switch name {
+	case "npm":
+		return NPM, nil
+	case "pnpm":
+		return PNPM, nil
+	case "bun":
+		return Bun, nil
+	case "yarn":
+		if major, err := yarnMajor(); err == nil && major >= 2 {
+			return YarnBerry, nil
+		}
+		return Yarn, nil
+	}
+	return "", fmt.Errorf("unknown package manager %q (use %s)", name, strings.Join(Names, ", "))
+}
+
+// Detect picks the package manager from the lockfile in the current directory.
+func Detect() (Manager, bool) {
+	switch {
+	case fsys.Exists("bun.lock") || fsys.Exists("bun.lockb"):
+		return Bun, true
+	case fsys.Exists("pnpm-lock.yaml"):
+		return PNPM, true
+	case fsys.Exists("yarn.lock"):
+		if fsys.Exists(".yarnrc.yml") {
+			return YarnBerry, true
+		}
+		return Yarn, true
+	case fsys.Exists("package-lock.json"):
+		return NPM, true
+	}
+	return "", false
+}
+
+// All lists every supported package manager.
+func All() []Manager {
+	return []Manager{NPM, Yarn, YarnBerry, PNPM, Bun}
+}
+
+// Lockfiles lists the lockfiles of every supported package manager.
+func Lockfiles() []string {
+	return []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "bun.lock", "bun.lockb"}
+}
+
+// Bin is the executable name.
+func (m Manager) Bin() string {
+	if m == YarnBerry {
+		return "yarn"
+	}
+	return string(m)
+}
+
+// Lockfile is the lockfile the manager writes.
+func (m Manager) Lockfile() string {
+	switch m {
+	case NPM:
+		return "package-lock.json"
+	case Yarn, YarnBerry:
+		return "yarn.lock"
+	case Bun:
+		return "bun.lock"
+	}
+	return "pnpm-lock.yaml"
+}
+
+// AddArgs returns the arguments that add packages as dependencies or devDependencies.
+func (m Manager) AddArgs(dev bool, packages ...string) []string {
+	var args []string
+	switch m {
+	case NPM, PNPM:
+		args = []string{"install"}
+		if dev {
+			args = append(args, "-D")
+		}
+	case Bun:
+		args = []string{"add"}
+		if dev {
+			args = append(args, "-d")
+		}
+	default:
+		args = []string{"add"}
+		if dev {
+			args = append(args, "-D")
+		}
+	}
+	return append(args, packages...)
+}
+
+// RemoveArgs returns the arguments that remove packages.
+func (m Manager) RemoveArgs(dev bool, packages ...string) []string {
+	var args []string
+	switch m {
+	case NPM:
+		args = []string{"uninstall"}
+	case PNPM:
+		args = []string{"remove"}
+		if dev {
+			args = append(args, "-D")
+		}
+	default:
+		args = []string{"remove"}
+	}
+	return append(args, packages...)
+}
+
+// RunArgs returns the arguments that run a package.json script.
+func (m Manager) RunArgs(script string) []string {
+	switch m {
+	case NPM, Bun:
+		return []string{"run", script}
+	}
+	return []string{script}
+}
+
+// Run is the shell command that runs a package.json script, for generated docs and configs.
+func (m Manager) Run(script string) string {
+	return strings.Join(append([]string{m.Bin()}, m.RunArgs(script)...), " ")
+}
+
+// ExecArgs returns the executable and arguments that run a locally installed binary.
+func (m Manager) ExecArgs(args ...string) (string, []string) {
+	switch m {
+	case NPM:
+		return "npx", args
+	case Bun:
+		return "bun", append([]string{"run"}, args...)
+	}
+	return m.Bin(), args
+}
+
+// Exec is the shell command that runs a locally installed binary.
+func (m Manager) Exec(command string) string {
+	bin, args := m.ExecArgs(command)
+	return strings.Join(append([]string{bin}, args...), " ")
+}
+
+// DlxArgs returns the executable and arguments that run a package without installing it.
+func (m Manager) DlxArgs(pkg string, args ...string) (string, []string) {
+	switch m {
+	case PNPM:
+		return "pnpm", append([]string{"dlx", pkg}, args...)
+	case YarnBerry:
+		return "yarn", append([]string{"dlx", pkg}, args...)
+	case Bun:
+		return "bunx", append([]string{pkg}, args...)
+	}
+	// npm and Yarn classic have no dlx; npx ships with Node.
+	return "npx", append([]string{"--yes", pkg}, args...)
+}
+
+// Dlx is the shell command form of DlxArgs.
+func (m Manager) Dlx(pkg string) string {
+	bin, args := m.DlxArgs(pkg)
+	return strings.Join(append([]string{bin}, args...), " ")
+}
+
+// CreateArgs returns the executable and arguments that run a create-* starter
+// into the current directory. An empty version runs the latest starter.
+func (m Manager) CreateArgs(starter, version string, args ...string) (string, []string) {
+	if version == "" {
+		version = "latest"
+	}
+	switch m {
+	case NPM:
+		// npm needs `--` to forward flags to the starter.
+		return "npm", append([]string{"create", starter + "@" + version, ".", "--"}, args...)
+	case PNPM:
+		return "pnpm", append([]string{"create", starter + "@" + version, "."}, args...)
+	}
+	// Yarn classic and bun create cannot pick a starter version, so run it directly.
+	return m.DlxArgs("create-"+starter+"@"+version, append([]string{"."}, args...)...)
+}
+
+// FrozenInstall is the shell command that installs exactly what the lockfile pins.
+func (m Manager) FrozenInstall() string {
+	switch m {
+	case NPM:
+		return "npm ci"
+	case YarnBerry:
+		return "yarn install --immutable"
+	}
+	return m.Bin() + " install --frozen-lockfile"
+}
@@ -0,0 +1,106 @@
+package pm
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetect_FromLockfile(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  Manager
+	}{
+		{[]string{"package-lock.json"}, NPM},
+		{[]string{"yarn.lock"}, Yarn},
+		{[]string{"yarn.lock", ".yarnrc.yml"}, YarnBerry},
+		{[]string{"pnpm-lock.yaml"}, PNPM},
+		{[]string{"bun.lock"}, Bun},
+		{[]string{"bun.lockb"}, Bun},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			for _, name := range tt.files {
+				if err := os.WriteFile(name, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, ok := Detect()
+			if !ok || got != tt.want {
+				t.Fatalf("Detect() = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		if got, ok := Detect(); ok {
+			t.Fatalf("Detect() = %q, want no match", got)
+		}
+	})
+}
+
+func TestParse_YarnVersion(t *testing.T) {
+	restore := yarnMajor
+	t.Cleanup(func() { yarnMajor = restore })
+
+	yarnMajor = func() (int, error) { return 1, nil }
+	if got, _ := Parse("yarn"); got != Yarn {
+		t.Errorf("Parse(yarn) with yarn 1 = %q, want %q", got, Yarn)
+	}
+
+	yarnMajor = func() (int, error) { return 4, nil }
+	if got, _ := Parse("yarn"); got != YarnBerry {
+		t.Errorf("Parse(yarn) with yarn 4 = %q, want %q", got, YarnBerry)
+	}
+
+	if _, err := Parse("pip"); err == nil {
+		t.Error("Parse(pip) should fail")
+	}
+}
+
+func TestManager_Dialects(t *testing.T) {
+	tests := []struct {
+		m      Manager
+		add    []string
+		remove []string
+		run    string
+		exec   string
+		dlx    string
+		create string
+	}{
+		{NPM, []string{"install", "-D", "x"}, []string{"uninstall", "x"}, "npm run dev", "npx eslint", "npx --yes x", "npm create vite@7.1.1 . -- --template react-ts"},
+		{Yarn, []string{"add", "-D", "x"}, []string{"remove", "x"}, "yarn dev", "yarn eslint", "npx --yes x", "npx --yes create-vite@7.1.1 . --template react-ts"},
+		{YarnBerry, []string{"add", "-D", "x"}, []string{"remove", "x"}, "yarn dev", "yarn eslint", "yarn dlx x", "yarn dlx create-vite@7.1.1 . --template react-ts"},
+		{PNPM, []string{"install", "-D", "x"}, []string{"remove", "-D", "x"}, "pnpm dev", "pnpm eslint", "pnpm dlx x", "pnpm create vite@7.1.1 . --template react-ts"},
+		{Bun, []string{"add", "-d", "x"}, []string{"remove", "x"}, "bun run dev", "bun run eslint", "bunx x", "bunx create-vite@7.1.1 . --template react-ts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.m), func(t *testing.T) {
+			if got := tt.m.AddArgs(true, "x"); !reflect.DeepEqual(got, tt.add) {
+				t.Errorf("AddArgs() = %v, want %v", got, tt.add)
+			}
+			if got := tt.m.RemoveArgs(true, "x"); !reflect.DeepEqual(got, tt.remove) {
+				t.Errorf("RemoveArgs() = %v, want %v", got, tt.remove)
+			}
+			if got := tt.m.Run("dev"); got != tt.run {
+				t.Errorf("Run() = %q, want %q", got, tt.run)
+			}
+			if got := tt.m.Exec("eslint"); got != tt.exec {
+				t.Errorf("Exec() = %q, want %q", got, tt.exec)
+			}
+			if got := tt.m.Dlx("x"); got != tt.dlx {
+				t.Errorf("Dlx() = %q, want %q", got, tt.dlx)
+			}
+			bin, args := tt.m.CreateArgs("vite", "7.1.1", "--template", "react-ts")
+			if got := strings.Join(append([]string{bin}, args...), " "); got != tt.create {
+				t.Errorf("CreateArgs() = %q, want %q", got, tt.create)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package preset saves and loads named scaffold configurations.
+//
+// A preset named "dashboard" is looked up, in order, as a file path (absolute,
+// relative to the working directory, or relative to the repository root), in
+// the repository's .go-sparky/presets directory, and in the user's
+// go-sparky/presets config directory.
+package preset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+// RepoDir is where a repository keeps shared presets, relative to its root.
+const RepoDir = ".go-sparky/presets"
+
+// Preset is a saved scaffold configuration.
+type Preset struct {
+	Name    string            `json:"name"`
+	Plan    plan.Plan         `json:"plan"`              // Name is left empty; the project name comes from the command line
+	Options map[string]string `json:"options,omitempty"` // scaffold flags that are not part of the plan
+}
+
+// UserDir returns the per-user preset directory.
+func UserDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-sparky", "presets"), nil
+}
+
+// RepoRoot returns the nearest parent directory (including the working
+// directory) that contains .git, or "" outside a repository.
+func RepoRoot() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := fsys.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Candidates lists the paths Load tries for ref, in order.
+func Candidates(ref string) []string {
+	var paths []string
+	root := RepoRoot()
+
+	if looksLikePath(ref) {
+		paths = append(paths, ref)
+		if root != "" && !filepath.IsAbs(ref) {
+			paths = append(paths, filepath.Join(root, ref))
+		}
+		return paths
+	}
+
+	file := ref + ".json"
+	if root != "" {
+		paths = append(paths, filepath.Join(root, RepoDir, file))
+	}
+	if dir, err := UserDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, file))
+	}
+	return paths
+}
+
+// Load finds and reads the preset ref, which is a name or a path to a preset file.
+func Load(ref string) (*Preset, error) {
+	candidates := Candidates(ref)
+	for _, path := range candidates {
+		data, err := fsys.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var p Preset
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("invalid preset %s: %w", path, err)
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		return &p, nil
+	}
+
+	return nil, fmt.Errorf("preset %q not found (looked in %s)", ref, strings.Join(candidates, ", "))
+}
+
+// Save writes the preset as indented JSON to path, creating parent directories.
+func (p *Preset) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := fsys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return fsys.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Dirs returns the directories searched for presets by name, in lookup order.
+func Dirs() []string {
+	var dirs []string
+	if root := RepoRoot(); root != "" {
+		dirs = append(dirs, filepath.Join(root, RepoDir))
+	}
+	if dir, err := UserDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// List returns the preset names saved in dir, sorted.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func looksLikePath(ref string) bool {
+	return strings.ContainsRune(ref, '/') || strings.ContainsRune(ref, filepath.Separator) || filepath.Ext(ref) == ".json"
+}
@@ -0,0 +1,125 @@
+package preset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+)
+
+// setup isolates the user config directory and changes into a fresh git repository.
+func setup(t *testing.T) (repo, user string) {
+	t.Helper()
+
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("HOME", config)
+
+	repo = t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	user, err := UserDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, user
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	_, user := setup(t)
+
+	want := &Preset{
+		Name:    "dashboard",
+		Plan:    plan.Plan{Bundler: plan.BundlerVite, PM: pm.NPM, Mantine: true, StyledApp: true},
+		Options: map[string]string{"no-git": "true"},
+	}
+	if err := want.Save(filepath.Join(user, "dashboard.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load("dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != want.Name || got.Plan != want.Plan || got.Options["no-git"] != "true" {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_RepoPresetWinsOverUser(t *testing.T) {
+	repo, user := setup(t)
+
+	userPreset := &Preset{Name: "team", Plan: plan.Plan{Bundler: plan.BundlerVite, Tailwind: true}}
+	if err := userPreset.Save(filepath.Join(user, "team.json")); err != nil {
+		t.Fatal(err)
+	}
+	repoPreset := &Preset{Name: "team", Plan: plan.Plan{Bundler: plan.BundlerBun}}
+	if err := repoPreset.Save(filepath.Join(repo, RepoDir, "team.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Plan.Bundler != plan.BundlerBun {
+		t.Errorf("Load picked the user preset, want the repository one")
+	}
+}
+
+func TestLoad_RepoRelativePath(t *testing.T) {
+	repo, _ := setup(t)
+
+	p := &Preset{Plan: plan.Plan{Bundler: plan.BundlerVite, Docker: true}}
+	if err := p.Save(filepath.Join(repo, "presets", "docker.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	nested := filepath.Join(repo, "apps", "web")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(nested)
+
+	got, err := Load("presets/docker.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Plan.Docker {
+		t.Errorf("Plan.Docker = false, want true")
+	}
+	if got.Name != "docker" {
+		t.Errorf("Name = %q, want the file name when the preset has none", got.Name)
+	}
+}
+
+func TestLoad_NotFound(t *testing.T) {
+	setup(t)
+
+	if _, err := Load("missing"); err == nil {
+		t.Fatal("Load succeeded for a missing preset")
+	}
+}
+
+func TestList(t *testing.T) {
+	_, user := setup(t)
+
+	for _, name := range []string{"b", "a"} {
+		if err := (&Preset{Name: name}).Save(filepath.Join(user, name+".json")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := List(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("List = %v, want [a b]", names)
+	}
+}
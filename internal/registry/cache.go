@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheTTL is how long registry metadata that rarely changes, such as engine requirements, is reused.
+const CacheTTL = 24 * time.Hour
+
+// ErrOffline is returned for lookups that need the network while offline.
+var ErrOffline = errors.New("offline")
+
+var offline bool
+
+// SetOffline makes lookups use only cached metadata.
+func SetOffline(v bool) {
+	offline = v
+}
+
+// Offline reports whether lookups are limited to cached metadata.
+func Offline() bool {
+	return offline
+}
+
+// cacheDir is where registry responses are kept, one directory per registry.
+var cacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-sparky", "registry"), nil
+}
+
+// cacheEntry is a cached /<name>/latest response.
+type cacheEntry struct {
+	Fetched time.Time       `json:"fetched"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// cachePath returns the cache file of name on registryURL.
+func cachePath(registryURL, name string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	host := registryURL
+	if u, err := url.Parse(registryURL); err == nil && u.Host != "" {
+		host = u.Host + u.Path
+	}
+	host = strings.NewReplacer(":", "_", "/", "_").Replace(strings.Trim(host, "/"))
+	return filepath.Join(dir, host, escapeName(name)+".json"), nil
+}
+
+// readCache returns the cached response for name and when it was fetched.
+// The cache lives outside the project, so it bypasses fsys and never shows up in dry runs.
+func readCache(registryURL, name string) ([]byte, time.Time, error) {
+	path, err := cachePath(registryURL, name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, time.Time{}, err
+	}
+	return entry.Body, entry.Fetched, nil
+}
+
+// writeCache stores a response. Failures only cost a refetch, so they are ignored.
+func writeCache(registryURL, name string, body []byte) {
+	path, err := cachePath(registryURL, name)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cacheEntry{Fetched: time.Now().UTC(), Body: body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	// Write then rename so concurrent lookups never read a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// Configured returns the registry npm would use in the current directory and
+// where it is set, following npm's precedence: the npm_config_registry
+// environment variable, the project .npmrc, then the user .npmrc.
+// Without any of them it returns DefaultURL and an empty source.
+func Configured() (url, source string) {
+	for _, name := range []string{"npm_config_registry", "NPM_CONFIG_REGISTRY"} {
+		if v := os.Getenv(name); v != "" {
+			return normalize(v), name
+		}
+	}
+
+	for _, path := range npmrcPaths() {
+		if v := npmrcRegistry(path); v != "" {
+			return normalize(v), path
+		}
+	}
+	return DefaultURL, ""
+}
+
+// npmrcPaths lists the .npmrc files npm reads, highest precedence first.
+func npmrcPaths() []string {
+	paths := []string{".npmrc"}
+	if user := os.Getenv("NPM_CONFIG_USERCONFIG"); user != "" {
+		return append(paths, user)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".npmrc"))
+	}
+	return paths
+}
+
+// npmrcRegistry returns the registry key of an .npmrc, expanding ${VAR} references like npm does.
+func npmrcRegistry(path string) string {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	registry := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "registry" {
+			continue
+		}
+		// The last assignment wins, as in npm's ini parser.
+		registry = os.Expand(strings.Trim(strings.TrimSpace(value), `"'`), os.Getenv)
+	}
+	return registry
+}
+
+// normalize drops the trailing slash so paths can be appended.
+func normalize(url string) string {
+	return strings.TrimRight(strings.TrimSpace(url), "/")
+}
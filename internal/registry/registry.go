@@ -0,0 +1,143 @@
+// Package registry looks up package versions on the npm registry.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultURL is the public npm registry.
+const DefaultURL = "https://registry.npmjs.org"
+
+// maxConcurrent bounds parallel lookups so a full scaffold stays polite to the registry.
+const maxConcurrent = 8
+
+var (
+	baseURL = "" // overrides the configured registry in tests
+	client  = &http.Client{Timeout: 5 * time.Second}
+)
+
+// Info is the part of a package's latest manifest go-sparky reads.
+type Info struct {
+	Version string `json:"version"`
+	Engines struct {
+		Node string `json:"node"`
+	} `json:"engines"`
+}
+
+// Latest returns the version published under the latest dist-tag.
+// It always asks the registry unless offline, and falls back to the cache when the registry cannot be reached.
+func Latest(name string) (string, error) {
+	info, err := LatestInfo(name, 0)
+	if err != nil {
+		return "", err
+	}
+	if info.Version == "" {
+		return "", fmt.Errorf("npm registry returned no version for %s", name)
+	}
+	return info.Version, nil
+}
+
+// LatestInfo returns the manifest published under the latest dist-tag, reusing a
+// cached copy younger than maxAge. When the registry cannot be reached, or
+// go-sparky is offline, a cached copy of any age is used instead.
+func LatestInfo(name string, maxAge time.Duration) (*Info, error) {
+	registryURL := URL()
+	cached, fetched, cacheErr := readCache(registryURL, name)
+	if cacheErr == nil && (offline || time.Since(fetched) < maxAge) {
+		return decodeInfo(name, cached)
+	}
+	if offline {
+		return nil, fmt.Errorf("%w: %s is not cached", ErrOffline, name)
+	}
+
+	body, err := fetchLatest(registryURL, name)
+	if err != nil {
+		if cacheErr == nil {
+			return decodeInfo(name, cached)
+		}
+		return nil, err
+	}
+
+	info, err := decodeInfo(name, body)
+	if err != nil {
+		return nil, err
+	}
+	writeCache(registryURL, name, body)
+	return info, nil
+}
+
+// URL returns the registry lookups go to.
+func URL() string {
+	if baseURL != "" {
+		return baseURL
+	}
+	url, _ := Configured()
+	return url
+}
+
+func fetchLatest(registryURL, name string) ([]byte, error) {
+	resp, err := client.Get(registryURL + "/" + escapeName(name) + "/latest")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from the npm registry: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("npm registry returned status %d for %s", resp.StatusCode, name)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func decodeInfo(name string, body []byte) (*Info, error) {
+	var info Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode %s package info: %w", name, err)
+	}
+	return &info, nil
+}
+
+// LatestAll resolves the latest version of every name concurrently.
+// Names that cannot be resolved are left out of the result.
+func LatestAll(names []string) map[string]string {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		versions = map[string]string{}
+		slots    = make(chan struct{}, maxConcurrent)
+	)
+
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			v, err := Latest(name)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			versions[name] = v
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return versions
+}
+
+// escapeName encodes the slash of scoped packages (@scope/name → @scope%2fname).
+func escapeName(name string) string {
+	if scope, pkg, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+		return scope + "%2f" + url.PathEscape(pkg)
+	}
+	return url.PathEscape(name)
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// useTestRegistry points lookups at url and the cache at a temporary directory.
+func useTestRegistry(t *testing.T, url string) {
+	t.Helper()
+	prevURL, prevCache, prevOffline := baseURL, cacheDir, offline
+	t.Cleanup(func() { baseURL, cacheDir, offline = prevURL, prevCache, prevOffline })
+
+	dir := t.TempDir()
+	baseURL = url
+	cacheDir = func() (string, error) { return dir, nil }
+}
+
+func TestLatestAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/zustand/latest":
+			w.Write([]byte(`{"name":"zustand","version":"5.0.8"}`))
+		case "/@tanstack%2freact-query/latest":
+			w.Write([]byte(`{"name":"@tanstack/react-query","version":"5.90.2"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	useTestRegistry(t, server.URL)
+
+	got := LatestAll([]string{"zustand", "@tanstack/react-query", "missing"})
+	want := map[string]string{"zustand": "5.0.8", "@tanstack/react-query": "5.90.2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("LatestAll() = %v, want %v", got, want)
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	user := filepath.Join(dir, "user.npmrc")
+	t.Setenv("NPM_CONFIG_USERCONFIG", user)
+	t.Setenv("npm_config_registry", "")
+	t.Setenv("NPM_CONFIG_REGISTRY", "")
+	t.Setenv("MIRROR_HOST", "npm.example.com")
+
+	if url, source := Configured(); url != DefaultURL || source != "" {
+		t.Errorf("Configured() = %q, %q, want the default registry", url, source)
+	}
+
+	if err := os.WriteFile(user, []byte("registry=https://user.example.com/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".npmrc", []byte("; mirror\n@acme:registry=https://scoped.example.com\nregistry = https://${MIRROR_HOST}/npm/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if url, source := Configured(); url != "https://npm.example.com/npm" || source != ".npmrc" {
+		t.Errorf("Configured() = %q, %q, want the project .npmrc registry", url, source)
+	}
+
+	t.Setenv("npm_config_registry", "https://env.example.com")
+	if url, source := Configured(); url != "https://env.example.com" || source != "npm_config_registry" {
+		t.Errorf("Configured() = %q, %q, want the environment registry", url, source)
+	}
+}
+
+func TestLatestInfo_CachesAndWorksOffline(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"name":"vite","version":"7.1.0","engines":{"node":"^20.19.0 || >=22.12.0"}}`))
+	}))
+	defer server.Close()
+	useTestRegistry(t, server.URL)
+
+	for range 2 {
+		info, err := LatestInfo("vite", time.Hour)
+		if err != nil {
+			t.Fatalf("LatestInfo() error = %v", err)
+		}
+		if info.Engines.Node != "^20.19.0 || >=22.12.0" {
+			t.Fatalf("engines.node = %q", info.Engines.Node)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("registry hit %d times, want 1 with a fresh cache", hits)
+	}
+
+	// An unreachable registry falls back to the cache of any age.
+	server.Close()
+	if v, err := Latest("vite"); err != nil || v != "7.1.0" {
+		t.Errorf("Latest() with the registry down = %q, %v, want the cached 7.1.0", v, err)
+	}
+
+	SetOffline(true)
+	if v, err := Latest("vite"); err != nil || v != "7.1.0" {
+		t.Errorf("Latest() offline = %q, %v, want the cached 7.1.0", v, err)
+	}
+	if _, err := Latest("react"); !errors.Is(err, ErrOffline) {
+		t.Errorf("Latest() offline without a cache error = %v, want ErrOffline", err)
+	}
+}
@@ -2,57 +2,59 @@ package runner
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 )
 
-// Run executes a command and streams stdout/stderr directly.
-func Run(cmd string, args ...string) error {
-	command := exec.Command(cmd, args...)
-	command.Stdout = os.Stdout
-	command.Stderr = os.Stderr
-	command.Stdin = os.Stdin
+// Command describes one external process invocation.
+type Command struct {
+	Name  string
+	Args  []string
+	Env   map[string]string // extra environment variables
+	Quiet bool              // capture output and only print it when the command fails
+	Stdin bool              // attach the terminal's stdin (interactive commands)
+	Probe bool              // read-only check whose exit status is the result
+}
 
-	return command.Run()
+// Executor runs commands. The default executor spawns real processes;
+// a dry-run recorder can stand in for it.
+type Executor interface {
+	Execute(c Command) error
 }
 
-// RunQuiet executes a command silently, only showing output if there's an error.
-func RunQuiet(cmd string, args ...string) error {
-	command := exec.Command(cmd, args...)
+// Exec is the Executor that spawns real processes.
+type Exec struct{}
 
-	var outBuf, errBuf bytes.Buffer
-	command.Stdout = &outBuf
-	command.Stderr = &errBuf
+// Execute runs the command with the requested output handling.
+func (Exec) Execute(c Command) error {
+	command := exec.Command(c.Name, c.Args...)
 
-	err := command.Run()
-	if err != nil {
-		// Print captured output on error
-		if outBuf.Len() > 0 {
-			fmt.Print(outBuf.String())
-		}
-		if errBuf.Len() > 0 {
-			fmt.Fprint(os.Stderr, errBuf.String())
+	if len(c.Env) > 0 {
+		command.Env = os.Environ()
+		for k, v := range c.Env {
+			command.Env = append(command.Env, fmt.Sprintf("%s=%s", k, v))
 		}
-		return err
 	}
 
-	return nil
-}
+	if c.Stdin {
+		command.Stdin = os.Stdin
+	}
 
-// RunQuietEnv executes a command with environment variables silently, only showing output on error.
-func RunQuietEnv(cmd string, env map[string]string, args ...string) error {
-	command := exec.Command(cmd, args...)
+	if c.Probe {
+		return command.Run()
+	}
+
+	if !c.Quiet {
+		command.Stdout = os.Stdout
+		command.Stderr = os.Stderr
+		return command.Run()
+	}
 
 	var outBuf, errBuf bytes.Buffer
 	command.Stdout = &outBuf
 	command.Stderr = &errBuf
-	command.Stdin = nil
-
-	command.Env = os.Environ()
-	for k, v := range env {
-		command.Env = append(command.Env, fmt.Sprintf("%s=%s", k, v))
-	}
 
 	err := command.Run()
 	if err != nil {
@@ -69,18 +71,47 @@ func RunQuietEnv(cmd string, env map[string]string, args ...string) error {
 	return nil
 }
 
+var current Executor = Exec{}
+
+// Use installs e as the active executor and returns a function that restores the previous one.
+func Use(e Executor) (restore func()) {
+	prev := current
+	current = e
+	return func() { current = prev }
+}
+
+// Run executes a command and streams stdout/stderr directly.
+func Run(cmd string, args ...string) error {
+	return current.Execute(Command{Name: cmd, Args: args, Stdin: true})
+}
+
+// RunQuiet executes a command silently, only showing output if there's an error.
+func RunQuiet(cmd string, args ...string) error {
+	return current.Execute(Command{Name: cmd, Args: args, Quiet: true})
+}
+
+// RunQuietEnv executes a command with environment variables silently, only showing output on error.
+func RunQuietEnv(cmd string, env map[string]string, args ...string) error {
+	return current.Execute(Command{Name: cmd, Args: args, Env: env, Quiet: true})
+}
+
 // RunEnv executes a command with additional environment variables.
+// Stdin is not attached to prevent interactive prompts when CI=1.
 func RunEnv(cmd string, env map[string]string, args ...string) error {
-	command := exec.Command(cmd, args...)
-	command.Stdout = os.Stdout
-	command.Stderr = os.Stderr
-	// Don't attach stdin to prevent interactive prompts when CI=1
-	command.Stdin = nil
-
-	command.Env = os.Environ()
-	for k, v := range env {
-		command.Env = append(command.Env, fmt.Sprintf("%s=%s", k, v))
+	return current.Execute(Command{Name: cmd, Args: args, Env: env})
+}
+
+// Succeeds runs a read-only probe and reports whether it exited with status 0.
+// An error is returned only when the command could not be run at all.
+func Succeeds(cmd string, args ...string) (bool, error) {
+	err := current.Execute(Command{Name: cmd, Args: args, Probe: true})
+	if err == nil {
+		return true, nil
 	}
 
-	return command.Run()
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	return false, err
 }
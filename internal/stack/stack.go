@@ -0,0 +1,66 @@
+// Package stack maps stack packages to the version ranges a scaffold installs.
+package stack
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// Built-in profile names accepted by --stack.
+const (
+	Pinned = "pinned" // ranges tested with this go-sparky release
+	Latest = "latest" // whatever the registry tags latest today
+)
+
+//go:embed profiles/pinned.json
+var pinnedProfile []byte
+
+// Profile maps package names to version ranges.
+// A .sparky.json manifest is also a valid profile: its recorded package
+// versions reproduce the scaffold it came from exactly.
+type Profile struct {
+	Name     string            `json:"name"`
+	Packages map[string]string `json:"packages"`
+}
+
+// Load returns a built-in profile by name, or reads a profile file.
+// An empty name selects the pinned profile.
+func Load(name string) (*Profile, error) {
+	switch name {
+	case "", Pinned:
+		return parse(pinnedProfile, Pinned)
+	case Latest:
+		return &Profile{Name: Latest, Packages: map[string]string{}}, nil
+	}
+
+	data, err := fsys.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("--stack must be %s, %s or a profile file: %w", Pinned, Latest, err)
+	}
+	return parse(data, strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)))
+}
+
+func parse(data []byte, fallbackName string) (*Profile, error) {
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid stack profile %s: %w", fallbackName, err)
+	}
+	if p.Name == "" {
+		p.Name = fallbackName
+	}
+	if p.Packages == nil {
+		p.Packages = map[string]string{}
+	}
+	return &p, nil
+}
+
+// Range returns the version range the profile pins for name.
+func (p *Profile) Range(name string) (string, bool) {
+	v, ok := p.Packages[name]
+	return v, ok && v != ""
+}
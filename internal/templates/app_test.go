@@ -40,3 +40,28 @@ func TestAppTemplateSelection(t *testing.T) {
 		}
 	})
 }
+
+func TestAppTest_QueriesTheSelectedTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       plan.Plan
+		queries []string
+	}{
+		{"basic", plan.Plan{}, []string{"Go Sparky mascot", "Get Started"}},
+		{"styled mantine", plan.Plan{Mantine: true, StyledApp: true, Zustand: true}, []string{"Go Sparky mascot", "Get Started"}},
+		{"zustand", plan.Plan{Zustand: true}, []string{"+1 treat", "Reset"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, test := AppTemplate(tt.p), AppTest(tt.p)
+			for _, q := range tt.queries {
+				if !strings.Contains(test, "'"+q+"'") {
+					t.Errorf("AppTest() does not query %q", q)
+				}
+				if !strings.Contains(app, q) {
+					t.Errorf("AppTemplate() has no %q for the test to find", q)
+				}
+			}
+		})
+	}
+}
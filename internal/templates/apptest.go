@@ -0,0 +1,111 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+)
+
+// VitestSetup is src/test/setup.ts, which adds the jest-dom matchers to expect.
+const VitestSetup = `import '@testing-library/jest-dom/vitest';
+`
+
+// BunTestHappyDOM is src/test/happydom.ts, preloaded by bun test to provide the DOM.
+const BunTestHappyDOM = `import { GlobalRegistrator } from '@happy-dom/global-registrator';
+
+GlobalRegistrator.register();
+`
+
+// BunTestSetup is src/test/setup.ts for bun test, which adds the jest-dom
+// matchers and unmounts rendered components after each test.
+const BunTestSetup = `import { cleanup } from '@testing-library/react';
+import * as matchers from '@testing-library/jest-dom/matchers';
+import { afterEach, expect } from 'bun:test';
+
+expect.extend(matchers);
+
+afterEach(() => {
+  cleanup();
+});
+`
+
+// BunTestMatchers is src/test/matchers.d.ts, which types the jest-dom matchers on bun:test's expect.
+const BunTestMatchers = `import type { TestingLibraryMatchers } from '@testing-library/jest-dom/matchers';
+
+declare module 'bun:test' {
+  interface Matchers<T> extends TestingLibraryMatchers<typeof expect.stringContaining, T> {}
+  interface AsymmetricMatchers extends TestingLibraryMatchers {}
+}
+`
+
+const basicAppTest = `import { render, screen } from '@testing-library/react';
+{{testImport}}
+
+import App from './App';
+
+describe('App', () => {
+  it('renders the mascot and the call to action', () => {
+    render(<App />);
+
+    expect(screen.getByRole('img', { name: 'Go Sparky mascot' })).toBeInTheDocument();
+    expect(screen.getByRole('button', { name: 'Get Started' })).toBeInTheDocument();
+  });
+});
+`
+
+const zustandAppTest = `import { render, screen } from '@testing-library/react';
+import userEvent from '@testing-library/user-event';
+{{testImport}}
+
+import App from './App';
+
+describe('App', () => {
+  it('counts treats in the store', async () => {
+    const user = userEvent.setup();
+    render(<App />);
+
+    await user.click(screen.getByRole('button', { name: '+1 treat' }));
+    expect(screen.getByText('2')).toBeInTheDocument();
+
+    await user.click(screen.getByRole('button', { name: 'Reset' }));
+    expect(screen.getByText('1')).toBeInTheDocument();
+  });
+});
+`
+
+const genericAppTest = `import { render } from '@testing-library/react';
+{{testImport}}
+
+import App from './App';
+
+describe('App', () => {
+  it('renders', () => {
+    const { container } = render(<App />);
+
+    expect(container).not.toBeEmptyDOMElement();
+  });
+});
+`
+
+// AppTest returns src/App.test.tsx for the App.tsx template AppTemplate selects,
+// written for Vitest on Vite and bun test on Bun.
+func AppTest(p plan.Plan) string {
+	test := basicAppTest
+	if p.Zustand && !(p.StyledApp && p.Mantine) {
+		test = zustandAppTest
+	}
+	return withTestImport(test, p)
+}
+
+// GenericAppTest returns the starter test for an App.tsx go-sparky did not generate.
+func GenericAppTest(p plan.Plan) string {
+	return withTestImport(genericAppTest, p)
+}
+
+func withTestImport(test string, p plan.Plan) string {
+	runner := "vitest"
+	if p.IsBun() {
+		runner = "bun:test"
+	}
+	return strings.ReplaceAll(test, "{{testImport}}", "import { describe, expect, it } from '"+runner+"';")
+}
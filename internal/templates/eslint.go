@@ -6,6 +6,16 @@ import (
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
+// vitestGlobals declares the globals Vitest injects (globals: true) in test files.
+const vitestGlobals = `  {
+    files: ["**/*.test.{ts,tsx}", "src/test/**"],
+    languageOptions: {
+      globals: {
+        ...globals.vitest,
+      },
+    },
+  }`
+
 // EslintConfig returns the eslint.config.js template.
 func EslintConfig(p plan.Plan) string {
 	ignores := []string{
@@ -23,6 +33,10 @@ func EslintConfig(p plan.Plan) string {
 		ignores = append(ignores, `"bun-env.d.ts"`)
 	}
 
+	if p.Playwright {
+		ignores = append(ignores, `"playwright-report"`, `"test-results"`)
+	}
+
 	ignoreBlock := strings.Join(ignores, ",\n      ")
 
 	globalsExtra := ""
@@ -49,6 +63,10 @@ func EslintConfig(p plan.Plan) string {
   }`)
 	}
 
+	if p.Vitest {
+		tailEntries = append(tailEntries, vitestGlobals)
+	}
+
 	tailBlock := strings.Join(tailEntries, ",\n")
 
 	return `import js from "@eslint/js";
@@ -156,6 +174,10 @@ func EslintConfigRelaxed(p plan.Plan) string {
 		ignores = append(ignores, `"bun-env.d.ts"`)
 	}
 
+	if p.Playwright {
+		ignores = append(ignores, `"playwright-report"`, `"test-results"`)
+	}
+
 	ignoreBlock := strings.Join(ignores, ",\n      ")
 
 	globalsExtra := ""
@@ -182,6 +204,10 @@ func EslintConfigRelaxed(p plan.Plan) string {
   }`)
 	}
 
+	if p.Vitest {
+		tailEntries = append(tailEntries, vitestGlobals)
+	}
+
 	tailBlock := strings.Join(tailEntries, ",\n")
 
 	return `import js from "@eslint/js";
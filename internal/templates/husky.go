@@ -4,28 +4,20 @@ import "github.com/hotslug/go-sparky/internal/plan"
 
 // LintStagedConfig returns the .lintstagedrc template.
 func LintStagedConfig(p plan.Plan) string {
-	commandPrefix := "pnpm"
-	if p.IsBun() {
-		commandPrefix = "bun run"
-	}
+	m := p.PackageManager()
 
 	return `{
-  "*.{js,jsx,ts,tsx}": ["` + commandPrefix + ` eslint --fix"],
-  "*.{js,jsx,ts,tsx,css,md,json}": ["` + commandPrefix + ` prettier --write"]
+  "*.{js,jsx,ts,tsx}": ["` + m.Exec("eslint --fix") + `"],
+  "*.{js,jsx,ts,tsx,css,md,json}": ["` + m.Exec("prettier --write") + `"]
 }
 `
 }
 
 // HuskyPreCommit returns the pre-commit hook content.
 func HuskyPreCommit(p plan.Plan) string {
-	commandPrefix := "pnpm"
-	if p.IsBun() {
-		commandPrefix = "bun run"
-	}
-
 	return `#!/bin/sh
 . "$(dirname "$0")/_/husky.sh"
 
-` + commandPrefix + ` lint-staged
+` + p.PackageManager().Exec("lint-staged") + `
 `
 }
@@ -6,7 +6,8 @@ import (
 	"github.com/hotslug/go-sparky/internal/plan"
 )
 
-// MainTemplate builds main.tsx with conditional providers.
+// MainTemplate builds main.tsx with conditional providers. A router renders
+// in place of <App />, innermost, since its routes render the app.
 func MainTemplate(p plan.Plan) string {
 	var externalImports []string
 	var internalImports []string
@@ -22,15 +23,32 @@ func MainTemplate(p plan.Plan) string {
 		)
 	}
 
+	if p.TanStackRouter {
+		externalImports = append(externalImports, "import { RouterProvider } from '@tanstack/react-router';")
+	}
+
 	externalImports = append(externalImports,
 		"import React from 'react';",
 		"import ReactDOM from 'react-dom/client';",
 	)
 
-	internalImports = append(internalImports,
-		"import App from './App';",
-		"import './index.css';",
-	)
+	if p.ReactRouter {
+		externalImports = append(externalImports, "import { RouterProvider } from 'react-router';")
+	}
+
+	app := "<App />"
+	if p.HasRouter() {
+		app = "<RouterProvider router={router} />"
+		internalImports = append(internalImports,
+			"import './index.css';",
+			"import { router } from './router';",
+		)
+	} else {
+		internalImports = append(internalImports,
+			"import App from './App';",
+			"import './index.css';",
+		)
+	}
 
 	var b strings.Builder
 
@@ -52,6 +70,10 @@ func MainTemplate(p plan.Plan) string {
 		b.WriteString("const queryClient = new QueryClient();\n\n")
 	}
 
+	if p.MSW {
+		b.WriteString(MockingStart + "\n")
+	}
+
 	b.WriteString("const rootElement = document.getElementById('root');\n")
 	b.WriteString("if (!rootElement) throw new Error('Root element not found');\n")
 	b.WriteString("const root = ReactDOM.createRoot(rootElement);\n\n")
@@ -62,7 +84,7 @@ func MainTemplate(p plan.Plan) string {
 		b.WriteString("  <React.StrictMode>\n")
 		b.WriteString("    <QueryClientProvider client={queryClient}>\n")
 		b.WriteString("      <MantineProvider>\n")
-		b.WriteString("        <App />\n")
+		b.WriteString("        " + app + "\n")
 		b.WriteString("      </MantineProvider>\n")
 		b.WriteString("      <ReactQueryDevtools initialIsOpen={false} />\n")
 		b.WriteString("    </QueryClientProvider>\n")
@@ -72,7 +94,7 @@ func MainTemplate(p plan.Plan) string {
 		b.WriteString("root.render(\n")
 		b.WriteString("  <React.StrictMode>\n")
 		b.WriteString("    <QueryClientProvider client={queryClient}>\n")
-		b.WriteString("      <App />\n")
+		b.WriteString("      " + app + "\n")
 		b.WriteString("      <ReactQueryDevtools initialIsOpen={false} />\n")
 		b.WriteString("    </QueryClientProvider>\n")
 		b.WriteString("  </React.StrictMode>\n")
@@ -81,14 +103,14 @@ func MainTemplate(p plan.Plan) string {
 		b.WriteString("root.render(\n")
 		b.WriteString("  <React.StrictMode>\n")
 		b.WriteString("    <MantineProvider>\n")
-		b.WriteString("      <App />\n")
+		b.WriteString("      " + app + "\n")
 		b.WriteString("    </MantineProvider>\n")
 		b.WriteString("  </React.StrictMode>\n")
 		b.WriteString(");\n")
 	default:
 		b.WriteString("root.render(\n")
 		b.WriteString("  <React.StrictMode>\n")
-		b.WriteString("    <App />\n")
+		b.WriteString("    " + app + "\n")
 		b.WriteString("  </React.StrictMode>\n")
 		b.WriteString(");\n")
 	}
@@ -0,0 +1,55 @@
+package templates
+
+// MockingStart starts the Mock Service Worker in development before main.tsx
+// renders, so the first requests are already answered by the handlers.
+const MockingStart = `if (import.meta.env.DEV) {
+  const { worker } = await import('./mocks/browser');
+  await worker.start({ onUnhandledRequest: 'bypass' });
+}
+`
+
+// MSWHandlers is src/mocks/handlers.ts with the sample endpoint.
+const MSWHandlers = `import { http, HttpResponse } from 'msw';
+
+export const handlers = [
+  http.get('/api/sparky', () =>
+    HttpResponse.json({
+      name: 'Sparky',
+      mood: 'Good boy.',
+      tricks: ['sit', 'scaffold', 'ship'],
+    }),
+  ),
+];
+`
+
+// MSWBrowser is src/mocks/browser.ts, which registers the handlers with the worker.
+const MSWBrowser = `import { setupWorker } from 'msw/browser';
+
+import { handlers } from './handlers';
+
+export const worker = setupWorker(...handlers);
+`
+
+// SparkyQuery is src/hooks/useSparky.ts, a sample TanStack Query hook that
+// reads the mocked endpoint.
+const SparkyQuery = `import { useQuery } from '@tanstack/react-query';
+
+export interface Sparky {
+  name: string;
+  mood: string;
+  tricks: string[];
+}
+
+export function useSparky() {
+  return useQuery({
+    queryKey: ['sparky'],
+    queryFn: async (): Promise<Sparky> => {
+      const response = await fetch('/api/sparky');
+      if (!response.ok) {
+        throw new Error('GET /api/sparky failed with ' + response.status);
+      }
+      return (await response.json()) as Sparky;
+    },
+  });
+}
+`
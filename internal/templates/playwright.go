@@ -0,0 +1,49 @@
+package templates
+
+import "github.com/hotslug/go-sparky/internal/plan"
+
+// PlaywrightSmokeTest is e2e/smoke.e2e.ts. The .e2e.ts suffix keeps Vitest and
+// bun test, which collect *.test.* and *.spec.* files, from running it.
+const PlaywrightSmokeTest = `import { expect, test } from '@playwright/test';
+
+test('renders the Sparky landing page', async ({ page }) => {
+  await page.goto('/');
+
+  await expect(page.getByRole('img', { name: 'Go Sparky mascot' })).toBeVisible();
+  await expect(page.getByText('Go-Sparky', { exact: true })).toBeVisible();
+});
+`
+
+// PlaywrightConfig returns playwright.config.ts, starting the dev server of the
+// plan's bundler before the tests run.
+func PlaywrightConfig(p plan.Plan) string {
+	command, port := devServer(p)
+	url := "http://localhost:" + port
+
+	return `import { defineConfig, devices } from '@playwright/test';
+
+export default defineConfig({
+  testDir: './e2e',
+  testMatch: '**/*.e2e.ts',
+  fullyParallel: true,
+  forbidOnly: !!process.env.CI,
+  retries: process.env.CI ? 2 : 0,
+  reporter: 'html',
+  use: {
+    baseURL: '` + url + `',
+    trace: 'on-first-retry',
+  },
+  projects: [
+    {
+      name: 'chromium',
+      use: { ...devices['Desktop Chrome'] },
+    },
+  ],
+  webServer: {
+    command: '` + command + `',
+    url: '` + url + `',
+    reuseExistingServer: !process.env.CI,
+  },
+});
+`
+}
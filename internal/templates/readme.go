@@ -18,28 +18,26 @@ func Readme(p plan.Plan) string {
 
 	bundlerLabel := "Vite"
 	entryFile := "src/main.tsx"
-	devPort := "5173"
 	storybookNote := "Storybook (Vite + React config; starter story in src/stories)"
 	tailwindNote := "Configured via `@tailwindcss/vite`"
-	quickstartCmd := "pnpm dev"
-	buildCmd := "pnpm build"
-	testCmd := "pnpm test"
-	lintCmd := "pnpm lint"
-	formatCmd := "pnpm format"
-	storybookCmd := "pnpm storybook dev -p 6006"
+	m := p.PackageManager()
+	quickstartCmd, devPort := devServer(p)
+	buildCmd := m.Run("build")
+	testCmd := m.Run("test")
+	lintCmd := m.Run("lint")
+	formatCmd := m.Run("format")
+	typecheckCmd := m.Run("typecheck")
+	storybookCmd := m.Run("storybook")
 
 	if p.IsBun() {
 		bundlerLabel = "Bun"
 		entryFile = "src/frontend.tsx"
-		devPort = "3000"
 		storybookNote = "Storybook (React config; starter story in src/stories)"
 		tailwindNote = "Configured via `bun-plugin-tailwind`"
-		quickstartCmd = "bun dev"
 		buildCmd = "bun run build"
 		testCmd = "bun test"
 		lintCmd = "bun lint"
 		formatCmd = "bun format"
-		storybookCmd = "bun run storybook dev -p 6006"
 	}
 
 	fmt.Fprintf(&b, "# %s\n\n", title)
@@ -56,12 +54,27 @@ func Readme(p plan.Plan) string {
 	if p.Mantine {
 		features = append(features, "Mantine UI (with Mantine PostCSS preset)")
 	}
+	if p.Shadcn {
+		features = append(features, "shadcn/ui (components.json; add components with the shadcn CLI)")
+	}
+	if p.Bulma {
+		features = append(features, "Bulma CSS (imported in src/index.css)")
+	}
 	if p.Zustand {
 		features = append(features, "Zustand state store (demo slice in src/stores/useSparkyStore.ts)")
 	}
 	if p.ReactQuery {
 		features = append(features, "TanStack Query + Devtools")
 	}
+	if p.MSW {
+		features = append(features, "MSW API mocks (handlers in src/mocks, started in development)")
+	}
+	if p.ReactRouter {
+		features = append(features, "React Router (routes in src/routes)")
+	}
+	if p.TanStackRouter {
+		features = append(features, "TanStack Router (file-based routes in src/routes)")
+	}
 	if p.Eslint {
 		features = append(features, "ESLint (React, TypeScript, a11y, import order, Prettier)")
 	}
@@ -71,6 +84,15 @@ func Readme(p plan.Plan) string {
 	if p.Husky {
 		features = append(features, "Husky + lint-staged pre-commit")
 	}
+	if p.Vitest {
+		features = append(features, "Vitest + Testing Library (starter test in src/App.test.tsx)")
+	}
+	if p.BunTest {
+		features = append(features, "bun test + happy-dom + Testing Library (starter test in src/App.test.tsx)")
+	}
+	if p.Playwright {
+		features = append(features, "Playwright end-to-end tests (smoke test in e2e/)")
+	}
 	if p.Storybook {
 		features = append(features, storybookNote)
 	}
@@ -98,12 +120,18 @@ func Readme(p plan.Plan) string {
 	b.WriteString("## Scripts\n")
 	b.WriteString("- `" + quickstartCmd + "` – start dev server\n")
 	b.WriteString("- `" + buildCmd + "` – production build\n")
-	b.WriteString("- `" + testCmd + "` – run unit tests\n")
+	b.WriteString("- `" + typecheckCmd + "` – type-check without emitting\n")
+	if p.IsBun() || p.Vitest {
+		b.WriteString("- `" + testCmd + "` – run unit tests\n")
+	}
+	if p.Playwright {
+		b.WriteString("- `" + m.Run("test:e2e") + "` – run Playwright end-to-end tests against the dev server\n")
+	}
 	if p.Eslint {
-		b.WriteString("- `" + lintCmd + "` – run ESLint\n")
+		b.WriteString("- `" + lintCmd + "` – run ESLint (`lint:fix` applies fixes)\n")
 	}
 	if p.Prettier {
-		b.WriteString("- `" + formatCmd + "` (optional) – run Prettier\n")
+		b.WriteString("- `" + formatCmd + "` – run Prettier (`format:check` only reports)\n")
 	}
 	if p.Storybook {
 		b.WriteString("- `" + storybookCmd + "` – run Storybook\n")
@@ -128,6 +156,22 @@ func Readme(p plan.Plan) string {
 		b.WriteString("- Styles in `src/index.css`\n\n")
 	}
 
+	if p.MSW {
+		b.WriteString("## API mocks\n")
+		b.WriteString("- Handlers: `src/mocks/handlers.ts` (sample `GET /api/sparky`)\n")
+		b.WriteString("- The worker starts in `src/main.tsx` in development only; production builds hit the real API\n")
+		if p.ReactQuery {
+			b.WriteString("- `src/hooks/useSparky.ts` queries the sample endpoint with TanStack Query\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if p.Playwright {
+		b.WriteString("## Playwright\n")
+		b.WriteString("- Download a browser once: `" + m.Exec("playwright install chromium") + "`\n")
+		b.WriteString("- Tests live in `e2e/` as `*.e2e.ts`; `playwright.config.ts` starts the dev server\n\n")
+	}
+
 	if p.Docker {
 		b.WriteString("## Docker\n")
 		b.WriteString("- Dev: `docker compose up dev` (http://localhost:" + devPort + ")\n")
@@ -151,3 +195,11 @@ func Readme(p plan.Plan) string {
 
 	return b.String()
 }
+
+// devServer returns the command that starts the dev server and the port it listens on.
+func devServer(p plan.Plan) (command, port string) {
+	if p.IsBun() {
+		return "bun dev", "3000"
+	}
+	return p.PackageManager().Run("dev"), "5173"
+}
@@ -0,0 +1,309 @@
+// Package txn journals the file changes of a go-sparky command so they can be
+// undone when the command fails partway through.
+package txn
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+// snapshot is the state of a path before the transaction first touched it.
+type snapshot struct {
+	existed bool
+	dir     bool
+	data    []byte
+	perm    os.FileMode
+}
+
+// Tx implements fsys.FS on top of the previously active backend. Every path is
+// snapshotted before its first write or removal, and directories it creates are
+// remembered so Rollback can delete them.
+type Tx struct {
+	base    fsys.FS
+	restore func()
+	start   string
+	cwd     string
+	files   []string
+	saved   map[string]snapshot
+	order   []string
+	created []string
+	entries map[string][]string // names in each directory Chdir entered as a project root
+}
+
+// Begin starts a transaction in the current directory and installs it as the
+// active fsys backend. The named files, relative to the working directory, are
+// snapshotted up front and again in every directory Chdir enters; use this for
+// files that external commands rewrite, such as package.json and lockfiles.
+// Entries that appear in the starting directory are left alone, since other
+// processes may be working next to the project.
+func Begin(files ...string) (*Tx, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tx{
+		base:    fsys.Current(),
+		start:   wd,
+		cwd:     wd,
+		files:   files,
+		saved:   map[string]snapshot{},
+		entries: map[string][]string{},
+	}
+	if err := t.saveFiles(wd); err != nil {
+		return nil, err
+	}
+
+	t.restore = fsys.Use(t)
+	return t, nil
+}
+
+// Commit keeps every change and stops journaling.
+func (t *Tx) Commit() {
+	t.restore()
+}
+
+// Rollback stops journaling, returns to the starting directory, restores every
+// snapshotted file and deletes the directories the transaction created.
+func (t *Tx) Rollback() error {
+	t.restore()
+
+	var errs []error
+	if err := t.base.Chdir(t.start); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i := len(t.order) - 1; i >= 0; i-- {
+		path := t.order[i]
+		if t.insideCreated(path) {
+			continue
+		}
+
+		s := t.saved[path]
+		switch {
+		case !s.existed:
+			if err := t.base.RemoveAll(path); err != nil {
+				errs = append(errs, err)
+			}
+		case s.dir:
+			if err := t.base.MkdirAll(path, s.perm); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			if err := t.base.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := t.base.WriteFile(path, s.data, s.perm); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// External commands bypass fsys, so whatever they added next to the
+	// original entries, such as node_modules, is only found by listing.
+	for dir, names := range t.entries {
+		if t.insideCreated(dir) {
+			continue
+		}
+		list, err := t.base.ReadDir(dir)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		for _, e := range list {
+			if slices.Contains(names, e.Name()) {
+				continue
+			}
+			if err := t.base.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for i := len(t.created) - 1; i >= 0; i-- {
+		if err := t.base.RemoveAll(t.created[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// saveFiles snapshots the state files in dir.
+func (t *Tx) saveFiles(dir string) error {
+	for _, name := range t.files {
+		if err := t.save(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enter snapshots the state files in the project root dir and lists its
+// entries, so Rollback can restore what external commands rewrite there and
+// delete what they add.
+func (t *Tx) enter(dir string) error {
+	if err := t.saveFiles(dir); err != nil {
+		return err
+	}
+	if _, ok := t.entries[dir]; ok {
+		return nil
+	}
+
+	list, err := t.base.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = e.Name()
+	}
+	t.entries[dir] = names
+	return nil
+}
+
+func (t *Tx) abs(name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(t.cwd, name)
+}
+
+// save records the current state of path unless it was already recorded.
+func (t *Tx) save(path string) error {
+	if _, ok := t.saved[path]; ok {
+		return nil
+	}
+
+	var s snapshot
+	info, err := t.base.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	case info.IsDir():
+		s = snapshot{existed: true, dir: true, perm: info.Mode().Perm()}
+	default:
+		data, err := t.base.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		s = snapshot{existed: true, data: data, perm: info.Mode().Perm()}
+	}
+
+	t.saved[path] = s
+	t.order = append(t.order, path)
+	return nil
+}
+
+// saveTree snapshots every entry below path, parents before children.
+func (t *Tx) saveTree(path string) error {
+	err := filepath.WalkDir(path, func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return t.save(p)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (t *Tx) insideCreated(path string) bool {
+	for _, dir := range t.created {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadFile reads through the underlying backend.
+func (t *Tx) ReadFile(name string) ([]byte, error) {
+	return t.base.ReadFile(name)
+}
+
+// ReadDir lists a directory through the underlying backend.
+func (t *Tx) ReadDir(name string) ([]os.DirEntry, error) {
+	return t.base.ReadDir(name)
+}
+
+// WriteFile snapshots the file, then writes it.
+func (t *Tx) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if err := t.save(t.abs(name)); err != nil {
+		return err
+	}
+	return t.base.WriteFile(name, data, perm)
+}
+
+// Stat describes a file through the underlying backend.
+func (t *Tx) Stat(name string) (os.FileInfo, error) {
+	return t.base.Stat(name)
+}
+
+// Mkdir creates a directory and remembers it for rollback.
+func (t *Tx) Mkdir(name string, perm os.FileMode) error {
+	if err := t.base.Mkdir(name, perm); err != nil {
+		return err
+	}
+	t.created = append(t.created, t.abs(name))
+	return nil
+}
+
+// MkdirAll creates a directory tree and remembers its outermost new directory.
+func (t *Tx) MkdirAll(path string, perm os.FileMode) error {
+	top := ""
+	for dir := t.abs(path); ; dir = filepath.Dir(dir) {
+		if _, err := t.base.Stat(dir); err == nil {
+			break
+		}
+		top = dir
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	if err := t.base.MkdirAll(path, perm); err != nil {
+		return err
+	}
+	if top != "" {
+		t.created = append(t.created, top)
+	}
+	return nil
+}
+
+// Remove snapshots the file, then deletes it.
+func (t *Tx) Remove(name string) error {
+	if err := t.save(t.abs(name)); err != nil {
+		return err
+	}
+	return t.base.Remove(name)
+}
+
+// RemoveAll snapshots the whole tree, then deletes it.
+func (t *Tx) RemoveAll(path string) error {
+	if err := t.saveTree(t.abs(path)); err != nil {
+		return err
+	}
+	return t.base.RemoveAll(path)
+}
+
+// Chdir changes into the project root, keeps relative paths resolvable for
+// rollback and journals the entries external commands add there.
+func (t *Tx) Chdir(dir string) error {
+	if err := t.base.Chdir(dir); err != nil {
+		return err
+	}
+	t.cwd = t.abs(dir)
+	return t.enter(t.cwd)
+}
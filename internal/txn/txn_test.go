@@ -0,0 +1,210 @@
+package txn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/fsys"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRollback_RestoresExistingProject(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeTestFile(t, "package.json", `{"name":"app"}`)
+	writeTestFile(t, "main.tsx", "original")
+	writeTestFile(t, "old.txt", "keep me")
+
+	tx, err := Begin("package.json", "pnpm-lock.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// External commands bypass fsys; the up-front snapshot still covers them.
+	writeTestFile(t, "package.json", `{"name":"app","dependencies":{}}`)
+	writeTestFile(t, "pnpm-lock.yaml", "lock")
+
+	if err := fsys.WriteFile("main.tsx", []byte("rewritten"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Remove("old.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.MkdirAll(filepath.Join("src", "stores"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("src", "stores", "store.ts"), []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if got := readTestFile(t, "package.json"); got != `{"name":"app"}` {
+		t.Errorf("package.json = %q, want original", got)
+	}
+	if got := readTestFile(t, "main.tsx"); got != "original" {
+		t.Errorf("main.tsx = %q, want original", got)
+	}
+	if got := readTestFile(t, "old.txt"); got != "keep me" {
+		t.Errorf("old.txt = %q, want restored", got)
+	}
+	for _, path := range []string{"pnpm-lock.yaml", "src"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be gone after rollback, stat error = %v", path, err)
+		}
+	}
+	if _, ok := fsys.Current().(*Tx); ok {
+		t.Error("Rollback() should uninstall the journal")
+	}
+}
+
+func TestRollback_DeletesNewProjectDirectory(t *testing.T) {
+	parent := t.TempDir()
+	t.Chdir(parent)
+
+	tx, err := Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Mkdir("my-app", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("my-app"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("vite.config.ts", []byte("config"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "my-app")); !os.IsNotExist(err) {
+		t.Fatalf("project directory should be deleted, stat error = %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wd != parent {
+		t.Fatalf("working directory = %s, want %s", wd, parent)
+	}
+}
+
+func TestRollback_FailedInstallIntoExistingDirectory(t *testing.T) {
+	parent := t.TempDir()
+	t.Chdir(parent)
+	if err := os.Mkdir("app", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join("app", "package.json"), `{"name":"app"}`)
+	writeTestFile(t, filepath.Join("app", "README.md"), "mine")
+
+	tx, err := Begin("package.json", "pnpm-lock.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("app"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The install rewrites package.json and leaves a lockfile and node_modules behind before failing.
+	writeTestFile(t, "package.json", `{"name":"app","dependencies":{"react":"^19.0.0"}}`)
+	writeTestFile(t, "pnpm-lock.yaml", "lock")
+	if err := os.MkdirAll(filepath.Join("node_modules", "react"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join("node_modules", "react", "index.js"), "react")
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if got := readTestFile(t, filepath.Join(parent, "app", "package.json")); got != `{"name":"app"}` {
+		t.Errorf("package.json = %q, want original", got)
+	}
+	if got := readTestFile(t, filepath.Join(parent, "app", "README.md")); got != "mine" {
+		t.Errorf("README.md = %q, want kept", got)
+	}
+	for _, name := range []string{"pnpm-lock.yaml", "node_modules"} {
+		if _, err := os.Stat(filepath.Join(parent, "app", name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be gone after rollback, stat error = %v", name, err)
+		}
+	}
+}
+
+func TestRollback_KeepsSiblingCreatedConcurrently(t *testing.T) {
+	parent := t.TempDir()
+	t.Chdir(parent)
+
+	tx, err := Begin("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Mkdir("my-app", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("my-app"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another terminal scaffolds a sibling project meanwhile.
+	sibling := filepath.Join(parent, "other-app", "src")
+	if err := os.MkdirAll(sibling, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(sibling, "work.ts"), "work")
+	writeTestFile(t, filepath.Join(parent, "notes.txt"), "notes")
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "my-app")); !os.IsNotExist(err) {
+		t.Errorf("project directory should be deleted, stat error = %v", err)
+	}
+	if got := readTestFile(t, filepath.Join(sibling, "work.ts")); got != "work" {
+		t.Errorf("sibling work.ts = %q, want kept", got)
+	}
+	if got := readTestFile(t, filepath.Join(parent, "notes.txt")); got != "notes" {
+		t.Errorf("notes.txt = %q, want kept", got)
+	}
+}
+
+func TestCommit_KeepsChanges(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tx, err := Begin("package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("package.json", []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tx.Commit()
+
+	if got := readTestFile(t, "package.json"); got != "{}" {
+		t.Fatalf("package.json = %q, want committed content", got)
+	}
+	if _, ok := fsys.Current().(*Tx); ok {
+		t.Error("Commit() should uninstall the journal")
+	}
+}
@@ -1,14 +1,15 @@
 package version
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
+
+	"github.com/hotslug/go-sparky/internal/logger"
+	"github.com/hotslug/go-sparky/internal/registry"
 )
 
 // NodeVersion represents a Node.js version
@@ -18,18 +19,6 @@ type NodeVersion struct {
 	Patch int
 }
 
-// npmPackageInfo represents the npm registry response
-type npmPackageInfo struct {
-	DistTags struct {
-		Latest string `json:"latest"`
-	} `json:"dist-tags"`
-	Versions map[string]struct {
-		Engines struct {
-			Node string `json:"node"`
-		} `json:"engines"`
-	} `json:"versions"`
-}
-
 // CheckNodeVersion checks if the installed Node.js version meets Vite's requirements.
 // It dynamically fetches the required version from npm registry.
 func CheckNodeVersion() error {
@@ -38,11 +27,7 @@ func CheckNodeVersion() error {
 		return fmt.Errorf("failed to check Node.js version: %w", err)
 	}
 
-	requirement, err := GetViteNodeRequirement()
-	if err != nil {
-		// Continue with fallback silently
-		requirement = ">=20.19.0 || >=22.12.0" // Fallback
-	}
+	requirement := ViteNodeRequirement()
 
 	if !IsVersionSupportedByRequirement(version, requirement) {
 		return &NodeVersionError{
@@ -97,34 +82,36 @@ func (e *NodeVersionError) Error() string {
 	return sb.String()
 }
 
-// GetViteNodeRequirement fetches the Node.js version requirement from Vite's npm package
-func GetViteNodeRequirement() (string, error) {
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get("https://registry.npmjs.org/vite/latest")
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch vite package info: %w", err)
-	}
-	defer resp.Body.Close()
+// viteNodeFallback is Vite's Node.js requirement when neither the registry nor the cache has it.
+const viteNodeFallback = ">=20.19.0 || >=22.12.0"
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("npm registry returned status %d", resp.StatusCode)
+// ViteNodeRequirement returns the Node.js requirement of the latest Vite, falling back to a built-in one.
+func ViteNodeRequirement() string {
+	requirement, err := GetViteNodeRequirement()
+	if errors.Is(err, registry.ErrOffline) {
+		logger.Warning("Offline and Vite's Node.js requirement is not cached; assuming " + viteNodeFallback + ".")
+		return viteNodeFallback
 	}
-
-	var pkgInfo struct {
-		Engines struct {
-			Node string `json:"node"`
-		} `json:"engines"`
+	if err != nil {
+		logger.Warning("Could not read Vite's Node.js requirement (" + err.Error() + "); assuming " + viteNodeFallback + ". Pass --offline to skip registry lookups.")
+		return viteNodeFallback
 	}
+	return requirement
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&pkgInfo); err != nil {
-		return "", fmt.Errorf("failed to decode package info: %w", err)
+// GetViteNodeRequirement returns the Node.js version requirement of Vite's npm package.
+// The registry response is cached for registry.CacheTTL, and offline only the cache is read.
+func GetViteNodeRequirement() (string, error) {
+	info, err := registry.LatestInfo("vite", registry.CacheTTL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch vite package info: %w", err)
 	}
 
-	if pkgInfo.Engines.Node == "" {
+	if info.Engines.Node == "" {
 		return "", fmt.Errorf("no node engine requirement found")
 	}
 
-	return pkgInfo.Engines.Node, nil
+	return info.Engines.Node, nil
 }
 
 // GetNodeVersion returns the currently installed Node.js version
@@ -135,13 +122,17 @@ func GetNodeVersion() (*NodeVersion, error) {
 		return nil, fmt.Errorf("node command not found or failed to execute: %w", err)
 	}
 
-	versionStr := strings.TrimSpace(string(output))
+	return ParseVersion(string(output))
+}
+
+// ParseVersion reads the leading X.Y.Z of a --version output such as "v20.9.0" or "1.22.19".
+func ParseVersion(output string) (*NodeVersion, error) {
+	versionStr := strings.TrimSpace(output)
 	// Remove 'v' prefix (e.g., "v20.9.0" -> "20.9.0")
 	versionStr = strings.TrimPrefix(versionStr, "v")
 
 	// Parse version string
-	re := regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
-	matches := re.FindStringSubmatch(versionStr)
+	matches := versionPattern.FindStringSubmatch(versionStr)
 	if len(matches) != 4 {
 		return nil, fmt.Errorf("invalid version format: %s", versionStr)
 	}
@@ -157,6 +148,13 @@ func GetNodeVersion() (*NodeVersion, error) {
 	}, nil
 }
 
+var versionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+// String formats the version as X.Y.Z.
+func (v *NodeVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
 // IsVersionSupportedByRequirement checks if a Node.js version satisfies a semver requirement string
 // Supports formats like: "^18.0.0 || >=20", ">=20.19.0 || >=22.12.0"
 func IsVersionSupportedByRequirement(v *NodeVersion, requirement string) bool {
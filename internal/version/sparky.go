@@ -0,0 +1,4 @@
+package version
+
+// Sparky is the go-sparky release version.
+const Sparky = "0.2.0"
@@ -0,0 +1,362 @@
+// Package wizard builds a scaffold plan by asking questions on a terminal.
+package wizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hotslug/go-sparky/internal/installer"
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/stack"
+)
+
+// ErrCancelled is returned when the user declines the summary.
+var ErrCancelled = errors.New("scaffold cancelled")
+
+// IsTerminal reports whether f is an interactive character device.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Wizard asks the scaffold questions on in and writes prompts to out.
+type Wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// New returns a wizard reading answers from in.
+func New(in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{in: bufio.NewReader(in), out: out}
+}
+
+// Run asks for every stack choice and returns the plan the equivalent flags would build.
+// base supplies the project name and the default answers, so configured defaults carry over.
+func (w *Wizard) Run(base plan.Plan) (plan.Plan, error) {
+	p := base
+
+	fmt.Fprintf(w.out, "\nLet's set up \x1b[38;2;255;185;0m%s\x1b[0m. Press Enter to accept the default in brackets.\n", p.Name)
+
+	bundlers := []string{"Vite", "Bun"}
+	def := 0
+	if p.IsBun() {
+		def = 1
+	}
+	choice, err := w.choose("Bundler", bundlers, def)
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	p.Bundler = plan.BundlerVite
+	if choice == 1 {
+		p.Bundler = plan.BundlerBun
+	}
+	// Drop the base plan's features the chosen bundler cannot build; the
+	// questions below offer only the ones it can.
+	for _, f := range installer.Features() {
+		if f.Bundler != "" && f.Bundler != p.Bundler && f.Selected != nil {
+			*f.Selected(&p) = false
+		}
+	}
+
+	p.PM = pm.Bun
+	if p.IsVite() {
+		managers := []pm.Manager{pm.PNPM, pm.NPM, pm.Yarn, pm.Bun}
+		names := []string{"pnpm", "npm", "yarn", "bun"}
+		def := 0
+		for i, name := range names {
+			if base.PM.Bin() == name {
+				def = i
+			}
+		}
+		choice, err := w.choose("Package manager", names, def)
+		if err != nil {
+			return plan.Plan{}, err
+		}
+		p.PM = managers[choice]
+		if p.PM == pm.Yarn {
+			// Parse tells Yarn classic from Berry by the installed version.
+			p.PM, _ = pm.Parse("yarn")
+		}
+	}
+
+	def = 0
+	switch {
+	case base.Mantine:
+		def = 1
+	case base.Shadcn:
+		def = 2
+	case base.Bulma:
+		def = 3
+	}
+	kit, err := w.choose("UI kit", []string{"None", "Mantine", "shadcn/ui", "Bulma"}, def)
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	p.Mantine, p.Shadcn, p.Bulma = kit == 1, kit == 2, kit == 3
+
+	if p.Mantine {
+		if p.StyledApp, err = w.confirm("Use the styled Mantine landing page for src/App.tsx?", base.StyledApp); err != nil {
+			return plan.Plan{}, err
+		}
+	} else {
+		p.StyledApp = false
+	}
+
+	if p.Shadcn {
+		fmt.Fprintln(w.out, "  shadcn/ui builds on Tailwind CSS, so Tailwind is included. Its init asks a few questions of its own.")
+		p.Tailwind = true
+	} else if p.Tailwind, err = w.confirm("Tailwind CSS?", base.Tailwind); err != nil {
+		return plan.Plan{}, err
+	}
+
+	if p.Zustand, err = w.confirm("State management with Zustand?", base.Zustand); err != nil {
+		return plan.Plan{}, err
+	}
+	if p.ReactQuery, err = w.confirm("Data fetching with TanStack Query?", base.ReactQuery); err != nil {
+		return plan.Plan{}, err
+	}
+	if p.Framer, err = w.confirm("Animations with Framer Motion?", base.Framer); err != nil {
+		return plan.Plan{}, err
+	}
+
+	routers := []string{"None", "React Router"}
+	if p.IsVite() {
+		routers = append(routers, "TanStack Router")
+	}
+	def = 0
+	switch {
+	case p.ReactRouter:
+		def = 1
+	case p.TanStackRouter:
+		def = 2
+	}
+	router, err := w.choose("Routing", routers, def)
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	p.ReactRouter, p.TanStackRouter = router == 1, router == 2
+
+	tooling, err := w.multi("Linting and formatting", []string{"ESLint", "Prettier", "Husky + lint-staged"}, []bool{base.Eslint, base.Prettier, base.Husky})
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	p.Eslint, p.Prettier, p.Husky = tooling[0], tooling[1], tooling[2]
+
+	unit := &p.Vitest
+	tests := []string{"Vitest", "Playwright end-to-end tests"}
+	if p.IsBun() {
+		unit = &p.BunTest
+		tests[0] = "bun test"
+	}
+	picked, err := w.multi("Testing", tests, []bool{*unit, p.Playwright})
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	*unit, p.Playwright = picked[0], picked[1]
+
+	if p.IsVite() {
+		if p.MSW, err = w.confirm("Mock API requests in development with MSW?", p.MSW); err != nil {
+			return plan.Plan{}, err
+		}
+	}
+
+	if p.Storybook, err = w.confirm("Storybook?", base.Storybook); err != nil {
+		return plan.Plan{}, err
+	}
+
+	deploy, err := w.multi("Deploy targets", []string{"Docker", "Vercel", "Netlify"}, []bool{base.Docker, base.Vercel, base.Netlify})
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	p.Docker, p.Vercel, p.Netlify = deploy[0], deploy[1], deploy[2]
+
+	if err := installer.ValidatePlan(p); err != nil {
+		return plan.Plan{}, err
+	}
+
+	w.summary(p)
+	ok, err := w.confirm("Create the project?", true)
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	if !ok {
+		return plan.Plan{}, ErrCancelled
+	}
+	return p, nil
+}
+
+// summary lists the selected stacks and the non-interactive command that builds the same plan.
+func (w *Wizard) summary(p plan.Plan) {
+	fmt.Fprintln(w.out, "\nSummary:")
+	fmt.Fprintf(w.out, "  Bundler:          %s\n", p.Bundler)
+	fmt.Fprintf(w.out, "  Package manager:  %s\n", p.PackageManager().Bin())
+
+	var stacks []string
+	for _, f := range installer.Features() {
+		if f.Enabled(p) {
+			stacks = append(stacks, f.Title)
+		}
+	}
+	if len(stacks) == 0 {
+		stacks = []string{"none"}
+	}
+	fmt.Fprintf(w.out, "  Stacks:           %s\n", strings.Join(stacks, ", "))
+
+	fmt.Fprintf(w.out, "\nRun the same scaffold without the wizard:\n  %s\n\n", CommandLine(p))
+}
+
+// CommandLine returns the go-sparky invocation whose flags produce p.
+func CommandLine(p plan.Plan) string {
+	target := p.Name
+	if p.Dir != "" {
+		target = p.Dir
+	}
+	args := []string{"go-sparky", "vite-setup", target}
+	if p.IsBun() {
+		args[1] = "new-bun"
+	}
+
+	for _, f := range installer.Features() {
+		if f.FlagUsage == "" || f.Selected == nil {
+			continue
+		}
+		if f.Enabled(p) != f.Default {
+			args = append(args, "--"+f.FlagName())
+		}
+	}
+
+	if p.StyledApp {
+		args = append(args, "--styled")
+	}
+	switch {
+	case p.ReactRouter:
+		args = append(args, "--router", installer.RouterReactRouter)
+	case p.TanStackRouter:
+		args = append(args, "--router", installer.RouterTanStack)
+	}
+	if p.IsVite() && p.PackageManager() != pm.PNPM {
+		args = append(args, "--pm", p.PackageManager().Bin())
+	}
+	if p.Stack != "" && p.Stack != stack.Pinned {
+		args = append(args, "--stack", p.Stack)
+	}
+	args = append(args, "--yes")
+
+	return strings.Join(args, " ")
+}
+
+func (w *Wizard) ask(question string) (string, error) {
+	fmt.Fprintf(w.out, "\033[1;36m?\033[0m %s ", question)
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", ErrCancelled
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// choose asks for one of options by number.
+func (w *Wizard) choose(question string, options []string, def int) (int, error) {
+	fmt.Fprintf(w.out, "\033[1;36m?\033[0m %s\n", question)
+	for i, option := range options {
+		marker := " "
+		if i == def {
+			marker = "›"
+		}
+		fmt.Fprintf(w.out, "  %s %d) %s\n", marker, i+1, option)
+	}
+
+	for {
+		answer, err := w.ask(fmt.Sprintf("Choose 1-%d [%d]:", len(options), def+1))
+		if err != nil {
+			return 0, err
+		}
+		if answer == "" {
+			return def, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		fmt.Fprintf(w.out, "  Please enter a number between 1 and %d.\n", len(options))
+	}
+}
+
+// confirm asks a yes/no question.
+func (w *Wizard) confirm(question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+
+	for {
+		answer, err := w.ask(question + " " + hint)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(w.out, "  Please answer y or n.")
+	}
+}
+
+// multi asks for any number of options as comma-separated numbers.
+func (w *Wizard) multi(question string, options []string, defs []bool) ([]bool, error) {
+	var defNums []string
+	fmt.Fprintf(w.out, "\033[1;36m?\033[0m %s\n", question)
+	for i, option := range options {
+		marker := " "
+		if defs[i] {
+			marker = "›"
+			defNums = append(defNums, strconv.Itoa(i+1))
+		}
+		fmt.Fprintf(w.out, "  %s %d) %s\n", marker, i+1, option)
+	}
+
+	def := strings.Join(defNums, ",")
+	if def == "" {
+		def = "none"
+	}
+
+	for {
+		answer, err := w.ask(fmt.Sprintf("Comma-separated numbers, or none [%s]:", def))
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return append([]bool(nil), defs...), nil
+		}
+
+		picked := make([]bool, len(options))
+		if strings.EqualFold(answer, "none") {
+			return picked, nil
+		}
+
+		valid := true
+		for _, field := range strings.Split(answer, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(options) {
+				valid = false
+				break
+			}
+			picked[n-1] = true
+		}
+		if valid {
+			return picked, nil
+		}
+		fmt.Fprintf(w.out, "  Please list numbers between 1 and %d, e.g. 1,3.\n", len(options))
+	}
+}
@@ -0,0 +1,131 @@
+package wizard
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hotslug/go-sparky/internal/plan"
+	"github.com/hotslug/go-sparky/internal/pm"
+	"github.com/hotslug/go-sparky/internal/stack"
+)
+
+func TestRun_BuildsPlanAndCommandLine(t *testing.T) {
+	answers := strings.Join([]string{
+		"",    // bundler: Vite
+		"2",   // package manager: npm
+		"2",   // UI kit: Mantine
+		"y",   // styled landing page
+		"n",   // Tailwind
+		"",    // Zustand
+		"n",   // TanStack Query
+		"",    // Framer Motion
+		"",    // routing: none
+		"1,2", // tooling: ESLint + Prettier, no Husky
+		"",    // testing: none
+		"",    // MSW
+		"",    // Storybook
+		"1,3", // deploy: Docker + Netlify
+		"",    // confirm
+	}, "\n") + "\n"
+
+	base := defaults("my-app")
+	base.Stack = stack.Pinned
+	p, err := New(strings.NewReader(answers), io.Discard).Run(base)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := plan.Plan{
+		Name: "my-app", Bundler: plan.BundlerVite, PM: pm.NPM, Stack: stack.Pinned,
+		Mantine: true, StyledApp: true, Zustand: true, Framer: true,
+		Eslint: true, Prettier: true, Docker: true, Netlify: true,
+	}
+	if p != want {
+		t.Fatalf("Run() plan =\n%+v\nwant\n%+v", p, want)
+	}
+
+	got := CommandLine(p)
+	wantCmd := "go-sparky vite-setup my-app --mantine --no-tailwind --no-react-query --no-husky --docker --netlify --styled --pm npm --yes"
+	if got != wantCmd {
+		t.Fatalf("CommandLine() =\n%s\nwant\n%s", got, wantCmd)
+	}
+}
+
+func TestRun_DefaultsComeFromBase(t *testing.T) {
+	base := defaults("app")
+	base.PM, base.Bulma, base.Framer, base.Docker = pm.NPM, true, false, true
+
+	p, err := New(strings.NewReader(strings.Repeat("\n", 14)), io.Discard).Run(base)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if p != base {
+		t.Fatalf("Run() plan =\n%+v\nwant the base plan\n%+v", p, base)
+	}
+}
+
+func TestRun_ShadcnIncludesTailwind(t *testing.T) {
+	answers := "2\n3\n" + strings.Repeat("\n", 9)
+	p, err := New(strings.NewReader(answers), io.Discard).Run(plan.Plan{Name: "app", Bundler: plan.BundlerVite})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !p.IsBun() || !p.Shadcn || !p.Tailwind || p.PM != pm.Bun {
+		t.Fatalf("Run() plan = %+v, want Bun + shadcn + Tailwind", p)
+	}
+}
+
+func TestRun_BunDropsViteOnlyFeatures(t *testing.T) {
+	base := defaults("app")
+	base.TanStackRouter, base.Vitest, base.MSW, base.Playwright = true, true, true, true
+
+	p, err := New(strings.NewReader("2\n"+strings.Repeat("\n", 11)), io.Discard).Run(base)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !p.IsBun() || p.TanStackRouter || p.Vitest || p.MSW || !p.Playwright {
+		t.Fatalf("Run() plan = %+v, want Bun + Playwright without the Vite-only features", p)
+	}
+}
+
+func TestRun_AsksRouterAndTests(t *testing.T) {
+	answers := "2\n1\n" + strings.Repeat("\n", 4) + "2\n\n1\n" + strings.Repeat("\n", 3)
+	p, err := New(strings.NewReader(answers), io.Discard).Run(plan.Plan{Name: "app", Bundler: plan.BundlerVite})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !p.IsBun() || !p.ReactRouter || !p.BunTest || p.Playwright {
+		t.Fatalf("Run() plan = %+v, want Bun + React Router + bun test", p)
+	}
+}
+
+func TestRun_DeclineAndEOFCancel(t *testing.T) {
+	decline := strings.Repeat("\n", 13) + "n\n"
+	if _, err := New(strings.NewReader(decline), io.Discard).Run(plan.Plan{Name: "app", Bundler: plan.BundlerVite}); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("declined Run() error = %v, want ErrCancelled", err)
+	}
+
+	if _, err := New(strings.NewReader(""), io.Discard).Run(plan.Plan{Name: "app", Bundler: plan.BundlerVite}); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("EOF Run() error = %v, want ErrCancelled", err)
+	}
+}
+
+// defaults returns the plan the scaffold flags produce when none are passed.
+func defaults(name string) plan.Plan {
+	return plan.Plan{
+		Name: name, Bundler: plan.BundlerVite, PM: pm.PNPM,
+		Tailwind: true, ReactQuery: true, Zustand: true, Framer: true,
+		Eslint: true, Prettier: true, Husky: true,
+	}
+}
+
+func TestCommandLine_Router(t *testing.T) {
+	p := defaults("my-app")
+	p.TanStackRouter = true
+	want := "go-sparky vite-setup my-app --router tanstack --yes"
+	if got := CommandLine(p); got != want {
+		t.Fatalf("CommandLine() =\n%s\nwant\n%s", got, want)
+	}
+}